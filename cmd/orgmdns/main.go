@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -10,15 +12,24 @@ import (
 	"github.com/osmargm1202/orgmdns/internal/app"
 	"github.com/osmargm1202/orgmdns/internal/config"
 	"github.com/osmargm1202/orgmdns/internal/logger"
+	"github.com/osmargm1202/orgmdns/pkg/orgmdns"
 )
 
 func main() {
+	os.Exit(run())
+}
+
+// run ejecuta la aplicación y retorna el código de salida
+func run() int {
 	debugFlag := flag.Bool("debug", false, "Activa logs de depuración")
+	dryRunFlag := flag.Bool("dry-run", false, "Muestra los cambios de un ciclo sin aplicarlos ni enviar correos")
+	planFormatFlag := flag.String("plan-format", "", "Imprime el plan del dry-run en stdout: table o json")
 	flag.Parse()
 
 	cfg, err := config.Load()
 	if err != nil {
-		panic(err)
+		fmt.Fprintf(os.Stderr, "Error de configuración: %v\n", err)
+		return 2
 	}
 
 	// Si se pasa --debug, prevalece sobre DEBUG env
@@ -26,27 +37,91 @@ func main() {
 		cfg.Debug = true
 	}
 
-	log := logger.Init(cfg.Debug)
+	// --dry-run y --plan-format prevalecen sobre DRY_RUN y PLAN_FORMAT
+	if *dryRunFlag {
+		cfg.DryRun = true
+	}
+	if *planFormatFlag != "" {
+		if err := cfg.SetPlanFormat(*planFormatFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "Error de configuración: %v\n", err)
+			return 2
+		}
+	}
+
+	// Con el plan impreso en stdout, los logs de consola van a stderr
+	console := os.Stdout
+	if cfg.DryRun && cfg.PlanFormat != "" {
+		console = os.Stderr
+	}
+	log := logger.InitWithConsole(cfg.Debug, console)
 	defer log.Close()
 
 	log.Info("Iniciando orgmdns...")
-	log.Debug(fmt.Sprintf("Configuración cargada: ZONE_ID=%s, SLEEP_TIME=%d minutos", cfg.ZoneID, cfg.SleepTime))
+	log.Debug(fmt.Sprintf("Configuración cargada: DNS_PROVIDER=%s, ZONE_PROVIDERS=%d, ZONE_ID=%s, ZONES=%d, SLEEP_TIME=%d minutos", cfg.DNSProvider, len(cfg.ZoneProviders), cfg.ZoneID, len(cfg.Zones), cfg.SleepTime))
 
-	// Manejo de señales para shutdown graceful
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	// Manejo de señales para shutdown graceful: la primera señal cancela el contexto,
+	// una segunda señal termina el proceso inmediatamente
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		log.Info(fmt.Sprintf("Recibida señal de terminación, cerrando (periodo de gracia: %v)...", cfg.ShutdownGraceDuration()))
+		stop()
+	}()
 
-	runner := app.NewRunner(cfg, log)
+	updater, err := app.NewUpdater(cfg, log)
+	if err != nil {
+		log.Error(fmt.Sprintf("Error inicializando proveedores DNS: %v", err))
+		return 1
+	}
 
-	// Ejecutar en goroutine para poder recibir señales
-	go func() {
-		if err := runner.Run(); err != nil {
-			log.Error(fmt.Sprintf("Error en runner: %v", err))
-			os.Exit(1)
+	// Verificar credenciales y permisos antes del primer ciclo. Los problemas de
+	// configuración detienen el arranque; los fallos de red se reintentan en los ciclos.
+	if cfg.Preflight {
+		if err := updater.Preflight(ctx); err != nil {
+			if errors.Is(err, orgmdns.ErrAuth) || errors.Is(err, orgmdns.ErrNotFound) {
+				log.Error(fmt.Sprintf("Verificación inicial fallida, revisa las credenciales y permisos: %v", err))
+				return 1
+			}
+			log.Error(fmt.Sprintf("No se pudo completar la verificación inicial, se continúa: %v", err))
 		}
-	}()
+	}
+
+	if cfg.DryRun {
+		return plan(ctx, cfg, log, updater)
+	}
+
+	if err := updater.Run(ctx); err != nil {
+		log.Error(fmt.Sprintf("Error en updater: %v", err))
+		return 1
+	}
+
+	log.Info("orgmdns detenido")
+	return 0
+}
 
-	// Esperar señal de terminación
-	<-sigChan
-	log.Info("Recibida señal de terminación, cerrando...")
+// plan ejecuta un ciclo en modo dry-run, imprime el plan en el formato configurado
+// y retorna el código de salida (1 si el ciclo tuvo errores)
+func plan(ctx context.Context, cfg *config.Config, log *logger.Logger, updater *orgmdns.Updater) int {
+	log.Info("Modo dry-run: se calcula el plan de un ciclo sin aplicar cambios ni enviar correos")
+
+	p, err := updater.Plan(ctx)
+	if p != nil {
+		var writeErr error
+		switch cfg.PlanFormat {
+		case "table":
+			writeErr = p.WriteTable(os.Stdout)
+		case "json":
+			writeErr = p.WriteJSON(os.Stdout)
+		}
+		if writeErr != nil {
+			log.Error(fmt.Sprintf("Error imprimiendo el plan: %v", writeErr))
+			return 1
+		}
+	}
+	if err != nil {
+		log.Error(fmt.Sprintf("El plan se calculó con errores: %v", err))
+		return 1
+	}
+	return 0
 }
@@ -1,13 +1,20 @@
 module github.com/osmargm1202/orgmdns
 
-go 1.23
+go 1.23.0
 
-require github.com/pion/stun v0.6.1
+require (
+	github.com/miekg/dns v1.1.68
+	github.com/pion/stun v0.6.1
+)
 
 require (
 	github.com/pion/dtls/v2 v2.2.7 // indirect
 	github.com/pion/logging v0.2.2 // indirect
 	github.com/pion/transport/v2 v2.2.1 // indirect
-	golang.org/x/crypto v0.8.0 // indirect
-	golang.org/x/sys v0.7.0 // indirect
+	golang.org/x/crypto v0.38.0 // indirect
+	golang.org/x/mod v0.24.0 // indirect
+	golang.org/x/net v0.40.0 // indirect
+	golang.org/x/sync v0.14.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/tools v0.33.0 // indirect
 )
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/osmargm1202/orgmdns/internal/config"
+	"github.com/osmargm1202/orgmdns/internal/ip"
+	"github.com/osmargm1202/orgmdns/internal/logger"
+)
+
+// newIPSource arma la fuente de IP pública de IP_SOURCES: una cadena que prueba las
+// fuentes en orden o, con IP_CONSENSUS, un consenso entre todas (con las fuentes por
+// defecto si IP_SOURCES está vacío). Con IP_DOUBLE_NAT_CHECK la fuente se envuelve
+// para comparar la IP con la dirección WAN del router. Retorna nil si se usa la
+// cadena por defecto.
+func newIPSource(cfg *config.Config, log *logger.Logger) ip.IPSource {
+	source := newDetectionSource(cfg, log)
+	if !cfg.IPDoubleNATCheck {
+		return source
+	}
+	if source == nil {
+		source = ip.DefaultChain()
+	}
+
+	log.Info("Detección de doble NAT activada: se compara la IP pública con la dirección WAN del router (UPnP, NAT-PMP/PCP)")
+	return ip.NewNATCheck(source, func(status ip.NATStatus) {
+		switch {
+		case status.Err != nil:
+			log.Info(fmt.Sprintf("No se pudo consultar la dirección WAN del router (%s): %v", status.RecordType, status.Err))
+		case status.DoubleNAT():
+			reason := "es distinta de la IP pública"
+			if err := ip.CheckPublic(status.RouterIP); err != nil {
+				reason = err.Error()
+			}
+			log.Info(fmt.Sprintf("Posible doble NAT (%s): el router (%s) informa la dirección WAN %s y la IP pública detectada es %s; la dirección WAN %s. Los puertos abiertos en el router no serán alcanzables desde internet",
+				status.RecordType, status.Router, status.RouterIP, status.PublicIP, reason))
+		default:
+			log.Info(fmt.Sprintf("La dirección WAN del router (%s) coincide con la IP pública %s: sin doble NAT", status.Router, status.PublicIP))
+		}
+	}, ip.DefaultRouterEntries(cfg.IPSourceTimeout)...)
+}
+
+// newDetectionSource arma la cadena o el consenso de IP_SOURCES, o nil para la
+// cadena por defecto
+func newDetectionSource(cfg *config.Config, log *logger.Logger) ip.IPSource {
+	if len(cfg.IPSources) == 0 && !cfg.IPConsensus {
+		return nil
+	}
+
+	entries := ip.DefaultEntries()
+	if len(cfg.IPSources) > 0 {
+		entries = newIPSourceEntries(cfg)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Source.Name())
+	}
+
+	if cfg.IPConsensus {
+		log.Info(fmt.Sprintf("Detección de IP por consenso (quorum %d): %s", cfg.IPQuorum, strings.Join(names, ", ")))
+		return ip.NewConsensus(cfg.IPQuorum, func(result ip.ConsensusResult) {
+			log.Info(fmt.Sprintf("Las fuentes de IP (%s) no coinciden, puede indicar doble NAT o un servicio defectuoso: %s", result.RecordType, result.Summary()))
+		}, entries...)
+	}
+
+	log.Info(fmt.Sprintf("Fuentes de IP pública (en orden): %s", strings.Join(names, ", ")))
+	return ip.NewChain(entries...)
+}
+
+// newIPSourceEntries crea las fuentes de IP_SOURCES en orden
+func newIPSourceEntries(cfg *config.Config) []ip.ChainEntry {
+	var entries []ip.ChainEntry
+	for _, sc := range cfg.IPSources {
+		entry := ip.ChainEntry{Timeout: sc.Timeout, Types: sc.Types}
+		switch sc.Kind {
+		case config.IPSourceSTUN:
+			entry.Source = ip.NewSTUNSource(sc.Target)
+		case config.IPSourceHTTP:
+			if sc.Target == "" {
+				// Sin URL: los servicios HTTP por defecto de cada familia
+				entries = append(entries, defaultHTTPEntries(entry)...)
+				continue
+			}
+			entry.Source = ip.NewHTTPSource(sc.Target)
+		case config.IPSourceDNS:
+			entry.Source = ip.NewDNSSource(sc.Method, sc.Resolvers()...)
+		case config.IPSourceInterface:
+			entry.Source = ip.NewInterfaceSource(sc.Target)
+		case config.IPSourceCommand:
+			entry.Source = ip.NewCommandSource(sc.Target)
+		case config.IPSourceUPnP:
+			entry.Source = ip.NewUPnPSource(sc.Target)
+		case config.IPSourceNATPMP:
+			entry.Source = ip.NewNATPMPSource(sc.Target)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// defaultHTTPEntries expande una entrada "http" sin URL en los servicios por
+// defecto de cada familia que atiende
+func defaultHTTPEntries(base ip.ChainEntry) []ip.ChainEntry {
+	var entries []ip.ChainEntry
+	for _, recordType := range []string{config.RecordTypeA, config.RecordTypeAAAA} {
+		if !base.Handles(recordType) {
+			continue
+		}
+		for _, url := range ip.DefaultHTTPServices(recordType) {
+			entries = append(entries, ip.ChainEntry{Source: ip.NewHTTPSource(url), Timeout: base.Timeout, Types: []string{recordType}})
+		}
+	}
+	return entries
+}
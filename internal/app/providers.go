@@ -0,0 +1,199 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/osmargm1202/orgmdns/internal/cloudflare"
+	"github.com/osmargm1202/orgmdns/internal/config"
+	"github.com/osmargm1202/orgmdns/internal/digitalocean"
+	"github.com/osmargm1202/orgmdns/internal/dyndns2"
+	"github.com/osmargm1202/orgmdns/internal/hetzner"
+	"github.com/osmargm1202/orgmdns/internal/logger"
+	"github.com/osmargm1202/orgmdns/internal/powerdns"
+	"github.com/osmargm1202/orgmdns/internal/provider"
+	"github.com/osmargm1202/orgmdns/internal/rfc2136"
+	"github.com/osmargm1202/orgmdns/internal/route53"
+)
+
+// newProviders crea los proveedores DNS usados por los registros configurados
+// (DNS_PROVIDER y ZONE_PROVIDERS). Para agregar un proveedor basta con crearlo aquí;
+// la reconciliación solo conoce provider.DNSProvider.
+func newProviders(cfg *config.Config, log *logger.Logger) (map[string]provider.DNSProvider, error) {
+	providers := make(map[string]provider.DNSProvider)
+
+	for _, record := range cfg.Records {
+		name, _ := cfg.ProviderFor(record.Name)
+		if _, ok := providers[name]; ok {
+			continue
+		}
+
+		var p provider.DNSProvider
+		switch name {
+		case config.ProviderCloudflare:
+			p = newCloudflareProvider(cfg, log)
+		case config.ProviderRFC2136:
+			rp, err := newRFC2136Provider(cfg, log)
+			if err != nil {
+				return nil, err
+			}
+			p = rp
+		case config.ProviderPowerDNS:
+			p = newPowerDNSProvider(cfg, log)
+		case config.ProviderDynDNS2:
+			p = newDynDNS2Provider(cfg, log)
+		case config.ProviderRoute53:
+			p = newRoute53Provider(cfg, log)
+		case config.ProviderDigitalOcean:
+			p = newDigitalOceanProvider(cfg, log)
+		case config.ProviderHetzner:
+			p = newHetznerProvider(cfg, log)
+		default:
+			return nil, fmt.Errorf("proveedor DNS desconocido: %s", name)
+		}
+		providers[name] = p
+		log.Info(fmt.Sprintf("Proveedor DNS habilitado: %s", name))
+	}
+
+	return providers, nil
+}
+
+// newCloudflareProvider crea el proveedor de Cloudflare con su resolvedor de zonas
+func newCloudflareProvider(cfg *config.Config, log *logger.Logger) *cloudflare.Provider {
+	cfClient := newCloudflareClient(cfg, log)
+
+	// Zonas explícitas (ZONES, ZONE_ID); el resto se descubre por nombre
+	var zoneIDs []string
+	if cfg.ZoneID != "" {
+		zoneIDs = append(zoneIDs, cfg.ZoneID)
+	}
+	zoneResolver := cloudflare.NewZoneResolver(cfClient, cfg.Zones, zoneIDs)
+
+	// El registro de prueba es una escritura: nunca en dry-run
+	p := cloudflare.NewProvider(cfClient, zoneResolver)
+	p.SetWriteProbe(cfg.CFPreflightProbe && !cfg.DryRun)
+	if cfg.CFPreflightProbe && cfg.DryRun {
+		log.Info("Modo dry-run: la verificación inicial no crea el registro de prueba de CF_PREFLIGHT_PROBE")
+	}
+	return p
+}
+
+// newCloudflareClient crea el cliente de Cloudflare con la política de reintentos configurada
+func newCloudflareClient(cfg *config.Config, log *logger.Logger) *cloudflare.Client {
+	cfClient := cloudflare.NewClient(cfg.AccountID, cfg.APIKey, cfg.APIEmail)
+
+	// Reintentos, presupuesto por ciclo y circuit breaker de la API
+	retryPolicy := cloudflare.DefaultRetryPolicy()
+	retryPolicy.MaxRetries = cfg.CFMaxRetries
+	retryPolicy.CycleBudget = cfg.CFRetryBudget
+	retryPolicy.BreakerThreshold = cfg.CFBreakerThreshold
+	retryPolicy.BreakerCooldown = time.Duration(cfg.CFBreakerCooldown) * time.Minute
+	cfClient.SetRetryPolicy(retryPolicy)
+
+	// Log del método de autenticación usado
+	if cfg.APIEmail != "" {
+		log.Info(fmt.Sprintf("Usando autenticación Cloudflare: API Key + Email (método legacy) - Email: %s", cfg.APIEmail))
+	} else {
+		log.Info(fmt.Sprintf("Usando autenticación Cloudflare: API Token (Bearer)"))
+	}
+
+	return cfClient
+}
+
+// newCloudflareIPLists crea la sincronización de las listas de CF_IP_LISTS. Si
+// Cloudflare también es proveedor DNS se comparte su cliente (y su circuit breaker).
+func newCloudflareIPLists(cfg *config.Config, log *logger.Logger, providers map[string]provider.DNSProvider) []*cloudflare.IPList {
+	if len(cfg.CFIPLists) == 0 {
+		return nil
+	}
+
+	var cfClient *cloudflare.Client
+	if cfProvider, ok := providers[config.ProviderCloudflare].(*cloudflare.Provider); ok {
+		cfClient = cfProvider.Client()
+	} else {
+		cfClient = newCloudflareClient(cfg, log)
+	}
+
+	lists := make([]*cloudflare.IPList, 0, len(cfg.CFIPLists))
+	for _, name := range cfg.CFIPLists {
+		log.Info(fmt.Sprintf("Lista de IPs de Cloudflare habilitada: %s (cuenta %s)", name, cfg.AccountID))
+		lists = append(lists, cloudflare.NewIPList(cfClient, name, cfg.CFIPListComment))
+	}
+	return lists
+}
+
+// newRFC2136Provider crea el proveedor RFC 2136 para las zonas asignadas en ZONE_PROVIDERS
+func newRFC2136Provider(cfg *config.Config, log *logger.Logger) (*rfc2136.Provider, error) {
+	rc := cfg.RFC2136
+	client, err := rfc2136.NewClient(rc.Server, rc.Network, rc.TSIGKey, rc.TSIGSecret, rc.TSIGAlgorithm)
+	if err != nil {
+		return nil, fmt.Errorf("RFC 2136: %w", err)
+	}
+
+	if rc.TSIGKey != "" {
+		log.Info(fmt.Sprintf("Usando RFC 2136 contra %s (%s) con TSIG %s (%s)", rc.Server, rc.Network, rc.TSIGKey, rc.TSIGAlgorithm))
+	} else {
+		log.Info(fmt.Sprintf("Usando RFC 2136 contra %s (%s) sin TSIG", rc.Server, rc.Network))
+	}
+
+	return rfc2136.NewProvider(client, cfg.ProviderZones(config.ProviderRFC2136), rc.TTL, rc.Prerequisites), nil
+}
+
+// newPowerDNSProvider crea el proveedor PowerDNS para las zonas asignadas en ZONE_PROVIDERS
+func newPowerDNSProvider(cfg *config.Config, log *logger.Logger) *powerdns.Provider {
+	pc := cfg.PowerDNS
+	log.Info(fmt.Sprintf("Usando API de PowerDNS en %s (servidor %s)", pc.APIURL, pc.ServerID))
+
+	client := powerdns.NewClient(pc.APIURL, pc.APIKey, pc.ServerID)
+	return powerdns.NewProvider(client, cfg.ProviderZones(config.ProviderPowerDNS), pc.TTL)
+}
+
+// newDynDNS2Provider crea el proveedor dyndns2
+func newDynDNS2Provider(cfg *config.Config, log *logger.Logger) *dyndns2.Provider {
+	dc := cfg.DynDNS2
+	log.Info(fmt.Sprintf("Usando dyndns2 en %s con usuario %s", dc.URL, dc.Username))
+
+	client := dyndns2.NewClient(dc.URL, dc.Username, dc.Password, dc.UserAgent)
+	return dyndns2.NewProvider(client)
+}
+
+// newRoute53Provider crea el proveedor Route 53 con las zonas de R53_ZONES y ZONE_PROVIDERS
+func newRoute53Provider(cfg *config.Config, log *logger.Logger) *route53.Provider {
+	rc := cfg.Route53
+
+	// Zonas de ZONE_PROVIDERS sin ID: se descubren por nombre
+	zones := make(map[string]string)
+	for _, zone := range cfg.ProviderZones(config.ProviderRoute53) {
+		zones[zone] = ""
+	}
+	for zone, id := range rc.Zones {
+		zones[zone] = id
+	}
+
+	client := route53.NewClient(route53.Credentials{
+		AccessKeyID:     rc.AccessKeyID,
+		SecretAccessKey: rc.SecretAccessKey,
+		SessionToken:    rc.SessionToken,
+	}, rc.Endpoint, rc.Region)
+	log.Info(fmt.Sprintf("Usando Route 53 en %s (región de firma %s)", client.Endpoint(), client.Region()))
+
+	return route53.NewProvider(client, zones, rc.TTL, cfg.Route53WaitDuration())
+}
+
+// newDigitalOceanProvider crea el proveedor DigitalOcean para las zonas asignadas en ZONE_PROVIDERS
+func newDigitalOceanProvider(cfg *config.Config, log *logger.Logger) *digitalocean.Provider {
+	dc := cfg.DigitalOcean
+	client := digitalocean.NewClient(dc.Token, dc.BaseURL)
+	log.Info(fmt.Sprintf("Usando API de DigitalOcean en %s", client.BaseURL()))
+
+	return digitalocean.NewProvider(client, cfg.ProviderZones(config.ProviderDigitalOcean), dc.TTL)
+}
+
+// newHetznerProvider crea el proveedor Hetzner DNS
+func newHetznerProvider(cfg *config.Config, log *logger.Logger) *hetzner.Provider {
+	hc := cfg.Hetzner
+	client := hetzner.NewClient(hc.Token, hc.BaseURL)
+	log.Info(fmt.Sprintf("Usando API de Hetzner DNS en %s", client.BaseURL()))
+
+	return hetzner.NewProvider(client)
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"github.com/osmargm1202/orgmdns/internal/config"
+	"github.com/osmargm1202/orgmdns/internal/logger"
+	"github.com/osmargm1202/orgmdns/pkg/orgmdns"
+)
+
+// NewUpdater arma el Updater de pkg/orgmdns desde la configuración del entorno:
+// proveedores de DNS_PROVIDER y ZONE_PROVIDERS, registros de RECORD_NAMES, listas
+// de IPs de CF_IP_LISTS y notificaciones por correo
+func NewUpdater(cfg *config.Config, log *logger.Logger) (*orgmdns.Updater, error) {
+	providers, err := newProviders(cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []orgmdns.Option{
+		orgmdns.WithLogger(log.Logger),
+		orgmdns.WithRecords(cfg.Records...),
+		orgmdns.WithDefaultProvider(cfg.DNSProvider),
+		orgmdns.WithNotifier(orgmdns.NewEmailNotifier(cfg.EmailFrom, cfg.EmailTo, cfg.EmailPassword, cfg.SMTPHost, cfg.SMTPPort)),
+		orgmdns.WithCreateMissing(cfg.CreateMissing),
+		orgmdns.WithInterval(cfg.SleepDuration()),
+		orgmdns.WithShutdownGrace(cfg.ShutdownGraceDuration()),
+		orgmdns.WithShutdownNotify(cfg.ShutdownNotify),
+		orgmdns.WithDryRun(cfg.DryRun),
+	}
+	for _, p := range providers {
+		opts = append(opts, orgmdns.WithProvider(p))
+	}
+	for zone, name := range cfg.ZoneProviders {
+		opts = append(opts, orgmdns.WithZoneProvider(zone, name))
+	}
+	if source := newIPSource(cfg, log); source != nil {
+		opts = append(opts, orgmdns.WithIPSource(source))
+	}
+	for _, list := range newCloudflareIPLists(cfg, log, providers) {
+		opts = append(opts, orgmdns.WithIPList(list))
+	}
+
+	return orgmdns.New(opts...)
+}
@@ -0,0 +1,95 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+var _ provider.Batcher = (*Provider)(nil)
+
+// Códigos y status con los que la API indica que el endpoint de lotes no existe
+// o no está disponible para la zona
+var batchUnsupportedCodes = []int{7000}
+
+// DNSRecordPatch es un PATCH de un registro dentro de un lote
+type DNSRecordPatch struct {
+	ID string `json:"id"`
+	DNSRecordUpdateRequest
+}
+
+// DNSRecordBatchRequest es el cuerpo de POST /zones/{zone_id}/dns_records/batch.
+// Cloudflare aplica el lote en una transacción: si una operación falla, no se
+// aplica ninguna.
+type DNSRecordBatchRequest struct {
+	Patches []DNSRecordPatch         `json:"patches,omitempty"`
+	Posts   []DNSRecordCreateRequest `json:"posts,omitempty"`
+}
+
+// DNSRecordBatchResult contiene los registros resultantes en el orden del lote
+type DNSRecordBatchResult struct {
+	Patches []DNSRecord `json:"patches"`
+	Posts   []DNSRecord `json:"posts"`
+}
+
+type DNSRecordBatchResponse struct {
+	Result  DNSRecordBatchResult `json:"result"`
+	Success bool                 `json:"success"`
+	Errors  []APIErrorDetail     `json:"errors"`
+}
+
+// BatchDNSRecords aplica varios cambios de registros de la zona en una sola
+// petición atómica. Si la API no admite lotes retorna un error que cumple
+// errors.Is con provider.ErrBatchUnsupported. Una respuesta exitosa puede traer
+// menos registros que el lote; se retornan los que llegaron.
+func (c *Client) BatchDNSRecords(ctx context.Context, zoneID string, batchReq DNSRecordBatchRequest) (*DNSRecordBatchResult, error) {
+	url := fmt.Sprintf("%s/zones/%s/dns_records/batch", c.baseURL, zoneID)
+
+	var batchResp DNSRecordBatchResponse
+	if err := c.do(ctx, "POST", url, batchReq, &batchResp); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.batchUnsupported() {
+			return nil, fmt.Errorf("%w: %w", provider.ErrBatchUnsupported, err)
+		}
+		return nil, err
+	}
+
+	return &batchResp.Result, nil
+}
+
+// batchUnsupported indica si el error corresponde a un endpoint de lotes no disponible
+func (e *APIError) batchUnsupported() bool {
+	return e.StatusCode == http.StatusMethodNotAllowed || e.StatusCode == http.StatusNotImplemented || e.hasCode(batchUnsupportedCodes)
+}
+
+// ApplyBatch aplica las creaciones y actualizaciones de la zona en un solo lote
+func (p *Provider) ApplyBatch(ctx context.Context, zone provider.Zone, creates []provider.Record, updates []provider.RecordChange) ([]provider.Record, []provider.Record, error) {
+	var batchReq DNSRecordBatchRequest
+	for _, change := range updates {
+		batchReq.Patches = append(batchReq.Patches, DNSRecordPatch{
+			ID:                     change.Current.ID,
+			DNSRecordUpdateRequest: updateRequest(change.Update),
+		})
+	}
+	for _, record := range creates {
+		batchReq.Posts = append(batchReq.Posts, createRequest(record))
+	}
+
+	result, err := p.client.BatchDNSRecords(ctx, zone.ID, batchReq)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	created := make([]provider.Record, 0, len(result.Posts))
+	for _, record := range result.Posts {
+		created = append(created, toProviderRecord(record))
+	}
+	updated := make([]provider.Record, 0, len(result.Patches))
+	for _, record := range result.Patches {
+		updated = append(updated, toProviderRecord(record))
+	}
+	return created, updated, nil
+}
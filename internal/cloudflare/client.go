@@ -1,63 +1,101 @@
 package cloudflare
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"net/http"
-	"time"
+	"net/url"
+	"strconv"
 )
 
+// Tamaño de página usado al listar registros DNS
+const listPerPage = 100
+
 type Client struct {
-	accountID string
-	apiKey    string
-	apiEmail  string // Para método legacy API Key + Email
-	zoneID    string
-	baseURL   string
+	accountID  string
+	apiKey     string
+	apiEmail   string // Para método legacy API Key + Email
+	baseURL    string
 	httpClient *http.Client
+	breaker    breaker // reintentos, presupuesto por ciclo y circuit breaker
 }
 
 type DNSRecord struct {
-	ID      string `json:"id"`
-	Type    string `json:"type"`
-	Name    string `json:"name"`
-	Content string `json:"content"`
-	TTL     int    `json:"ttl"`
+	ID        string   `json:"id"`
+	Type      string   `json:"type"`
+	Name      string   `json:"name"`
+	Content   string   `json:"content"`
+	TTL       int      `json:"ttl"`
+	Proxied   bool     `json:"proxied"`
+	Proxiable bool     `json:"proxiable"`
+	Comment   string   `json:"comment"`
+	Tags      []string `json:"tags"`
 }
 
 type DNSRecordResponse struct {
-	Result []DNSRecord `json:"result"`
-	Success bool       `json:"success"`
-	Errors  []struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-	} `json:"errors"`
+	Result     []DNSRecord      `json:"result"`
+	ResultInfo ResultInfo       `json:"result_info"`
+	Success    bool             `json:"success"`
+	Errors     []APIErrorDetail `json:"errors"`
+}
+
+// ResultInfo contiene la información de paginación de las respuestas de listado
+type ResultInfo struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"per_page"`
+	Count      int `json:"count"`
+	TotalCount int `json:"total_count"`
+	TotalPages int `json:"total_pages"`
+}
+
+// ListDNSRecordsFilter filtra el listado de registros en el servidor.
+// Los campos vacíos no se envían.
+type ListDNSRecordsFilter struct {
+	Name string // nombre exacto (FQDN)
+	Type string // A, AAAA, etc.
 }
 
+// DNSRecordUpdateRequest es el cuerpo del PATCH de un registro DNS.
+// Solo se envían los campos no vacíos (nil para punteros).
 type DNSRecordUpdateRequest struct {
-	Content string `json:"content"`
+	Content string    `json:"content,omitempty"`
+	TTL     int       `json:"ttl,omitempty"`
+	Proxied *bool     `json:"proxied,omitempty"`
+	Comment *string   `json:"comment,omitempty"`
+	Tags    *[]string `json:"tags,omitempty"`
+}
+
+// DNSRecordCreateRequest es el cuerpo para crear un registro DNS
+type DNSRecordCreateRequest struct {
+	Type    string   `json:"type"`
+	Name    string   `json:"name"`
+	Content string   `json:"content"`
+	TTL     int      `json:"ttl"`
+	Proxied bool     `json:"proxied"`
+	Comment string   `json:"comment,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+type DNSRecordCreateResponse struct {
+	Result  DNSRecord        `json:"result"`
+	Success bool             `json:"success"`
+	Errors  []APIErrorDetail `json:"errors"`
 }
 
 type DNSRecordUpdateResponse struct {
-	Result  DNSRecord `json:"result"`
-	Success bool      `json:"success"`
-	Errors  []struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-	} `json:"errors"`
+	Result  DNSRecord        `json:"result"`
+	Success bool             `json:"success"`
+	Errors  []APIErrorDetail `json:"errors"`
 }
 
-func NewClient(accountID, apiKey, zoneID, apiEmail string) *Client {
+func NewClient(accountID, apiKey, apiEmail string) *Client {
 	return &Client{
-		accountID: accountID,
-		apiKey:    apiKey,
-		apiEmail:  apiEmail,
-		zoneID:    zoneID,
-		baseURL:   "https://api.cloudflare.com/client/v4",
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		accountID:  accountID,
+		apiKey:     apiKey,
+		apiEmail:   apiEmail,
+		baseURL:    "https://api.cloudflare.com/client/v4",
+		httpClient: &http.Client{},
+		breaker:    breaker{policy: DefaultRetryPolicy()},
 	}
 }
 
@@ -73,115 +111,78 @@ func (c *Client) setAuthHeaders(req *http.Request) {
 	}
 }
 
-// ListDNSRecords obtiene todos los registros DNS tipo A de la zona
-func (c *Client) ListDNSRecords() ([]DNSRecord, error) {
-	url := fmt.Sprintf("%s/zones/%s/dns_records?type=A", c.baseURL, c.zoneID)
+// ListDNSRecords obtiene todos los registros DNS de la zona que cumplen el filtro,
+// recorriendo todas las páginas de result_info
+func (c *Client) ListDNSRecords(ctx context.Context, zoneID string, filter ListDNSRecordsFilter) ([]DNSRecord, error) {
+	var records []DNSRecord
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creando request: %w", err)
-	}
-
-	c.setAuthHeaders(req)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error haciendo request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error leyendo respuesta: %w", err)
-	}
+	for page := 1; ; page++ {
+		recordResp, err := c.listDNSRecordsPage(ctx, zoneID, filter, page)
+		if err != nil {
+			return nil, err
+		}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error de API: status %d, body: %s", resp.StatusCode, string(body))
-	}
+		records = append(records, recordResp.Result...)
 
-	var recordResp DNSRecordResponse
-	if err := json.Unmarshal(body, &recordResp); err != nil {
-		return nil, fmt.Errorf("error parseando respuesta: %w", err)
-	}
-
-	if !recordResp.Success {
-		errMsg := "error desconocido"
-		if len(recordResp.Errors) > 0 {
-			errMsg = recordResp.Errors[0].Message
+		// Parar cuando no hay más páginas (o la API no informa paginación)
+		info := recordResp.ResultInfo
+		if info.TotalPages <= page || len(recordResp.Result) == 0 {
+			break
 		}
-		return nil, fmt.Errorf("API retornó error: %s", errMsg)
 	}
 
-	return recordResp.Result, nil
+	return records, nil
 }
 
-// GetDNSRecordByName obtiene el registro DNS A por nombre (filtra localmente como en Python)
-func (c *Client) GetDNSRecordByName(name string) (*DNSRecord, error) {
-	// Obtener todos los registros tipo A (como hace Python)
-	records, err := c.ListDNSRecords()
-	if err != nil {
-		return nil, fmt.Errorf("error listando registros DNS: %w", err)
+// listDNSRecordsPage obtiene una página del listado de registros DNS
+func (c *Client) listDNSRecordsPage(ctx context.Context, zoneID string, filter ListDNSRecordsFilter, page int) (*DNSRecordResponse, error) {
+	query := url.Values{}
+	if filter.Name != "" {
+		query.Set("name", filter.Name)
 	}
-
-	// Filtrar localmente por nombre (como hace Python)
-	for _, record := range records {
-		if record.Name == name {
-			return &record, nil
-		}
+	if filter.Type != "" {
+		query.Set("type", filter.Type)
 	}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(listPerPage))
 
-	return nil, fmt.Errorf("no se encontró registro DNS con nombre %s", name)
-}
-
-// UpdateDNSRecordIP actualiza la IP de un registro DNS A
-func (c *Client) UpdateDNSRecordIP(recordID, newIP string) error {
-	url := fmt.Sprintf("%s/zones/%s/dns_records/%s", c.baseURL, c.zoneID, recordID)
+	reqURL := fmt.Sprintf("%s/zones/%s/dns_records?%s", c.baseURL, zoneID, query.Encode())
 
-	updateReq := DNSRecordUpdateRequest{
-		Content: newIP,
-	}
-
-	jsonData, err := json.Marshal(updateReq)
-	if err != nil {
-		return fmt.Errorf("error serializando request: %w", err)
+	var recordResp DNSRecordResponse
+	if err := c.do(ctx, "GET", reqURL, nil, &recordResp); err != nil {
+		return nil, err
 	}
 
-	req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("error creando request: %w", err)
-	}
+	return &recordResp, nil
+}
 
-	c.setAuthHeaders(req)
-	req.Header.Set("Content-Type", "application/json")
+// UpdateDNSRecord actualiza los campos indicados de un registro DNS (PATCH)
+// y retorna el registro resultante
+func (c *Client) UpdateDNSRecord(ctx context.Context, zoneID, recordID string, updateReq DNSRecordUpdateRequest) (*DNSRecord, error) {
+	url := fmt.Sprintf("%s/zones/%s/dns_records/%s", c.baseURL, zoneID, recordID)
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("error haciendo request: %w", err)
+	var updateResp DNSRecordUpdateResponse
+	if err := c.do(ctx, "PATCH", url, updateReq, &updateResp); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("error leyendo respuesta: %w", err)
-	}
+	return &updateResp.Result, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("error de API: status %d, body: %s", resp.StatusCode, string(body))
-	}
+// CreateDNSRecord crea un registro DNS en la zona y retorna el registro creado
+func (c *Client) CreateDNSRecord(ctx context.Context, zoneID string, createReq DNSRecordCreateRequest) (*DNSRecord, error) {
+	url := fmt.Sprintf("%s/zones/%s/dns_records", c.baseURL, zoneID)
 
-	var updateResp DNSRecordUpdateResponse
-	if err := json.Unmarshal(body, &updateResp); err != nil {
-		return fmt.Errorf("error parseando respuesta: %w", err)
+	var createResp DNSRecordCreateResponse
+	if err := c.do(ctx, "POST", url, createReq, &createResp); err != nil {
+		return nil, err
 	}
 
-	if !updateResp.Success {
-		errMsg := "error desconocido"
-		if len(updateResp.Errors) > 0 {
-			errMsg = updateResp.Errors[0].Message
-		}
-		return fmt.Errorf("API retornó error: %s", errMsg)
-	}
+	return &createResp.Result, nil
+}
 
-	return nil
+// DeleteDNSRecord borra un registro DNS de la zona
+func (c *Client) DeleteDNSRecord(ctx context.Context, zoneID, recordID string) error {
+	url := fmt.Sprintf("%s/zones/%s/dns_records/%s", c.baseURL, zoneID, recordID)
+	return c.do(ctx, "DELETE", url, nil, nil)
 }
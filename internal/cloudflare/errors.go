@@ -0,0 +1,135 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// Categorías de error de la API. Se comprueban con errors.Is sobre el error
+// retornado por el cliente o con los helpers IsAuthError, IsNotFound, etc.
+// Son las mismas categorías de internal/provider.
+var (
+	ErrAuth        = provider.ErrAuth
+	ErrNotFound    = provider.ErrNotFound
+	ErrRateLimited = provider.ErrRateLimited
+	ErrValidation  = provider.ErrValidation
+	ErrUnavailable = provider.ErrUnavailable
+	ErrUnverified  = provider.ErrUnverified
+)
+
+// Códigos de error de Cloudflare usados para clasificar respuestas
+var (
+	authErrorCodes       = []int{6003, 6111, 9103, 9106, 9107, 9109, 10000, 10001}
+	notFoundErrorCodes   = []int{7003, 81044}
+	rateLimitErrorCodes  = []int{971, 10429}
+	validationErrorCodes = []int{1004, 9005, 9021, 81053, 81057, 81058}
+)
+
+// APIErrorDetail es un error individual del campo errors de la respuesta
+type APIErrorDetail struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// APIError es un error retornado por la API de Cloudflare (status no exitoso o success=false)
+type APIError struct {
+	StatusCode int
+	Errors     []APIErrorDetail
+	RequestID  string // header CF-Ray, útil para soporte de Cloudflare
+	Method     string
+	Path       string
+	Body       string // cuerpo crudo (recortado) si no se pudo interpretar
+}
+
+func (e *APIError) Error() string {
+	var msgs []string
+	for _, detail := range e.Errors {
+		msgs = append(msgs, fmt.Sprintf("%d: %s", detail.Code, detail.Message))
+	}
+
+	msg := fmt.Sprintf("error de API de Cloudflare: %s %s status %d", e.Method, e.Path, e.StatusCode)
+	if len(msgs) > 0 {
+		msg += " [" + strings.Join(msgs, "; ") + "]"
+	} else if e.Body != "" {
+		msg += ", body: " + e.Body
+	}
+	if e.RequestID != "" {
+		msg += " (cf-ray " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// Is permite usar errors.Is(err, ErrAuth) y el resto de categorías
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrAuth:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden || e.hasCode(authErrorCodes)
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.hasCode(notFoundErrorCodes)
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests || e.hasCode(rateLimitErrorCodes)
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity || e.hasCode(validationErrorCodes)
+	case ErrUnavailable:
+		// Un 5xx que persiste tras los reintentos: la API no está disponible
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// hasCode indica si alguno de los errores tiene uno de los códigos indicados
+func (e *APIError) hasCode(codes []int) bool {
+	for _, detail := range e.Errors {
+		for _, code := range codes {
+			if detail.Code == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsAuthError indica si el error es de autenticación o permisos (token inválido, sin permiso)
+func IsAuthError(err error) bool { return errors.Is(err, ErrAuth) }
+
+// IsNotFound indica si el recurso (zona o registro) no existe
+func IsNotFound(err error) bool { return errors.Is(err, ErrNotFound) }
+
+// IsRateLimited indica si la API rechazó la petición por rate limit
+func IsRateLimited(err error) bool { return errors.Is(err, ErrRateLimited) }
+
+// IsValidation indica si la API rechazó la petición por datos inválidos
+func IsValidation(err error) bool { return errors.Is(err, ErrValidation) }
+
+// newAPIError construye un APIError a partir de la respuesta HTTP
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("CF-Ray"),
+	}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.Path = resp.Request.URL.Path
+	}
+
+	var envelope apiEnvelope
+	if err := json.Unmarshal(body, &envelope); err == nil && len(envelope.Errors) > 0 {
+		apiErr.Errors = envelope.Errors
+	} else {
+		apiErr.Body = truncate(string(body), 512)
+	}
+	return apiErr
+}
+
+// truncate recorta s a max bytes
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "..."
+}
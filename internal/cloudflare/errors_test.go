@@ -0,0 +1,78 @@
+package cloudflare
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		codes      []int
+		wantIs     []error
+	}{
+		{name: "401", statusCode: http.StatusUnauthorized, wantIs: []error{ErrAuth}},
+		{name: "403", statusCode: http.StatusForbidden, wantIs: []error{ErrAuth}},
+		{name: "token inválido (código 6003 con 400)", statusCode: http.StatusBadRequest, codes: []int{6003}, wantIs: []error{ErrAuth, ErrValidation}},
+		{name: "sin permiso (código 10000 con 200)", statusCode: http.StatusOK, codes: []int{10000}, wantIs: []error{ErrAuth}},
+		{name: "404", statusCode: http.StatusNotFound, wantIs: []error{ErrNotFound}},
+		{name: "registro inexistente (código 81044)", statusCode: http.StatusOK, codes: []int{81044}, wantIs: []error{ErrNotFound}},
+		{name: "429", statusCode: http.StatusTooManyRequests, wantIs: []error{ErrRateLimited}},
+		{name: "rate limit (código 10429)", statusCode: http.StatusOK, codes: []int{10429}, wantIs: []error{ErrRateLimited}},
+		{name: "registro duplicado (código 81057)", statusCode: http.StatusOK, codes: []int{81057}, wantIs: []error{ErrValidation}},
+		{name: "422", statusCode: http.StatusUnprocessableEntity, wantIs: []error{ErrValidation}},
+		{name: "502", statusCode: http.StatusBadGateway, wantIs: []error{ErrUnavailable}},
+		{name: "código desconocido", statusCode: http.StatusOK, codes: []int{99999}},
+	}
+
+	all := []error{ErrAuth, ErrNotFound, ErrRateLimited, ErrValidation, ErrUnavailable}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := &APIError{StatusCode: tt.statusCode}
+			for _, code := range tt.codes {
+				apiErr.Errors = append(apiErr.Errors, APIErrorDetail{Code: code, Message: "detalle"})
+			}
+
+			for _, target := range all {
+				want := false
+				for _, w := range tt.wantIs {
+					want = want || w == target
+				}
+				if got := errors.Is(apiErr, target); got != want {
+					t.Errorf("errors.Is(%v, %v) = %v, want %v", apiErr, target, got, want)
+				}
+			}
+		})
+	}
+
+	// Las categorías re-exportadas son las de internal/provider
+	if !errors.Is(&APIError{StatusCode: http.StatusForbidden}, provider.ErrAuth) {
+		t.Error("ErrAuth no coincide con provider.ErrAuth")
+	}
+}
+
+func TestBatchUnsupported(t *testing.T) {
+	tests := []struct {
+		name   string
+		apiErr *APIError
+		want   bool
+	}{
+		{name: "405", apiErr: &APIError{StatusCode: http.StatusMethodNotAllowed}, want: true},
+		{name: "501", apiErr: &APIError{StatusCode: http.StatusNotImplemented}, want: true},
+		{name: "código 7000", apiErr: &APIError{StatusCode: http.StatusBadRequest, Errors: []APIErrorDetail{{Code: 7000}}}, want: true},
+		{name: "validación", apiErr: &APIError{StatusCode: http.StatusBadRequest, Errors: []APIErrorDetail{{Code: 81057}}}},
+		{name: "5xx", apiErr: &APIError{StatusCode: http.StatusServiceUnavailable}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.apiErr.batchUnsupported(); got != tt.want {
+				t.Errorf("batchUnsupported() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
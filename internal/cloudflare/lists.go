@@ -0,0 +1,384 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// Espera entre consultas de una operación masiva de listas y tiempo máximo de espera
+const (
+	bulkOperationPoll    = 1 * time.Second
+	bulkOperationTimeout = 60 * time.Second
+)
+
+var (
+	_ provider.IPList        = (*IPList)(nil)
+	_ provider.IPListPlanner = (*IPList)(nil)
+	_ provider.CycleAware    = (*IPList)(nil)
+)
+
+// List es una lista de cuenta (/accounts/{account_id}/rules/lists)
+type List struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Kind        string `json:"kind"` // ip, redirect, hostname, asn
+	NumItems    int    `json:"num_items"`
+	Description string `json:"description"`
+}
+
+type ListsResponse struct {
+	Result  []List           `json:"result"`
+	Success bool             `json:"success"`
+	Errors  []APIErrorDetail `json:"errors"`
+}
+
+// ListItem es un elemento de una lista de IPs
+type ListItem struct {
+	ID      string `json:"id,omitempty"`
+	IP      string `json:"ip,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+type ListItemsResponse struct {
+	Result     []ListItem       `json:"result"`
+	ResultInfo ListCursorInfo   `json:"result_info"`
+	Success    bool             `json:"success"`
+	Errors     []APIErrorDetail `json:"errors"`
+}
+
+// ListCursorInfo contiene la paginación por cursor del listado de elementos
+type ListCursorInfo struct {
+	Cursors struct {
+		After string `json:"after"`
+	} `json:"cursors"`
+}
+
+// BulkOperation es el estado de una operación asíncrona sobre una lista
+type BulkOperation struct {
+	ID     string `json:"id"`
+	Status string `json:"status"` // pending, running, completed, failed
+	Error  string `json:"error"`
+}
+
+type bulkOperationIDResponse struct {
+	Result struct {
+		OperationID string `json:"operation_id"`
+	} `json:"result"`
+}
+
+type bulkOperationResponse struct {
+	Result BulkOperation `json:"result"`
+}
+
+// deleteListItemsRequest es el cuerpo del DELETE de elementos de una lista
+type deleteListItemsRequest struct {
+	Items []ListItem `json:"items"`
+}
+
+// ListLists obtiene las listas de la cuenta (GET /accounts/{account_id}/rules/lists)
+func (c *Client) ListLists(ctx context.Context) ([]List, error) {
+	if c.accountID == "" {
+		return nil, fmt.Errorf("ACCOUNT_ID es requerido para usar listas de la cuenta: %w", ErrValidation)
+	}
+	reqURL := fmt.Sprintf("%s/accounts/%s/rules/lists", c.baseURL, c.accountID)
+
+	var listsResp ListsResponse
+	if err := c.do(ctx, "GET", reqURL, nil, &listsResp); err != nil {
+		return nil, err
+	}
+
+	return listsResp.Result, nil
+}
+
+// ListItems obtiene todos los elementos de una lista siguiendo el cursor de paginación
+func (c *Client) ListItems(ctx context.Context, listID string) ([]ListItem, error) {
+	var items []ListItem
+	cursor := ""
+
+	for {
+		query := url.Values{}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		reqURL := fmt.Sprintf("%s/accounts/%s/rules/lists/%s/items", c.baseURL, c.accountID, listID)
+		if len(query) > 0 {
+			reqURL += "?" + query.Encode()
+		}
+
+		var itemsResp ListItemsResponse
+		if err := c.do(ctx, "GET", reqURL, nil, &itemsResp); err != nil {
+			return nil, err
+		}
+		items = append(items, itemsResp.Result...)
+
+		cursor = itemsResp.ResultInfo.Cursors.After
+		if cursor == "" {
+			return items, nil
+		}
+	}
+}
+
+// CreateListItems agrega elementos a una lista y espera a que la operación termine
+// (POST /accounts/{account_id}/rules/lists/{list_id}/items)
+func (c *Client) CreateListItems(ctx context.Context, listID string, items []ListItem) error {
+	reqURL := fmt.Sprintf("%s/accounts/%s/rules/lists/%s/items", c.baseURL, c.accountID, listID)
+
+	var opResp bulkOperationIDResponse
+	if err := c.do(ctx, "POST", reqURL, items, &opResp); err != nil {
+		return err
+	}
+
+	return c.waitBulkOperation(ctx, opResp.Result.OperationID)
+}
+
+// DeleteListItems elimina elementos de una lista por ID y espera a que la operación termine
+// (DELETE /accounts/{account_id}/rules/lists/{list_id}/items)
+func (c *Client) DeleteListItems(ctx context.Context, listID string, itemIDs []string) error {
+	reqURL := fmt.Sprintf("%s/accounts/%s/rules/lists/%s/items", c.baseURL, c.accountID, listID)
+
+	payload := deleteListItemsRequest{}
+	for _, id := range itemIDs {
+		payload.Items = append(payload.Items, ListItem{ID: id})
+	}
+
+	var opResp bulkOperationIDResponse
+	if err := c.do(ctx, "DELETE", reqURL, payload, &opResp); err != nil {
+		return err
+	}
+
+	return c.waitBulkOperation(ctx, opResp.Result.OperationID)
+}
+
+// waitBulkOperation consulta una operación masiva hasta que termine
+// (GET /accounts/{account_id}/rules/lists/bulk_operations/{operation_id})
+func (c *Client) waitBulkOperation(ctx context.Context, operationID string) error {
+	if operationID == "" {
+		return nil
+	}
+	reqURL := fmt.Sprintf("%s/accounts/%s/rules/lists/bulk_operations/%s", c.baseURL, c.accountID, operationID)
+	deadline := time.Now().Add(bulkOperationTimeout)
+
+	for {
+		var opResp bulkOperationResponse
+		if err := c.do(ctx, "GET", reqURL, nil, &opResp); err != nil {
+			return fmt.Errorf("error consultando operación %s: %w", operationID, err)
+		}
+
+		switch opResp.Result.Status {
+		case "completed":
+			return nil
+		case "failed":
+			return fmt.Errorf("la operación %s sobre la lista falló: %s: %w", operationID, opResp.Result.Error, ErrValidation)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("la operación %s no terminó en %v (estado %s): %w", operationID, bulkOperationTimeout, opResp.Result.Status, provider.ErrUnavailable)
+		}
+
+		timer := time.NewTimer(bulkOperationPoll)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// IPList mantiene la IP pública actual en una lista de IPs de la cuenta (por ejemplo
+// la usada en reglas WAF). Los elementos que gestiona se marcan con un comentario;
+// al cambiar la IP se agrega la nueva y se eliminan los elementos gestionados (o la
+// IP anterior) de la misma familia.
+type IPList struct {
+	client  *Client
+	name    string
+	comment string
+
+	mu       sync.Mutex
+	listID   string            // se resuelve por nombre al primer uso
+	previous map[string]string // tipo de registro -> última IP sincronizada
+}
+
+// NewIPList crea la sincronización de la lista indicada por nombre. comment marca
+// los elementos gestionados por orgmdns.
+func NewIPList(client *Client, name, comment string) *IPList {
+	return &IPList{
+		client:   client,
+		name:     name,
+		comment:  comment,
+		previous: make(map[string]string),
+	}
+}
+
+func (l *IPList) Name() string {
+	return l.name
+}
+
+// BeginCycle reinicia el presupuesto de reintentos del cliente (compartido con el
+// proveedor DNS si usan el mismo) y omite la lista si el circuit breaker está abierto
+func (l *IPList) BeginCycle() error {
+	l.client.BeginCycle()
+	if state := l.client.BreakerState(); state == BreakerOpen {
+		return fmt.Errorf("circuit breaker %s: %w", state, provider.ErrUnavailable)
+	}
+	return nil
+}
+
+// EndCycle reporta si el circuit breaker quedó abierto o semiabierto tras el ciclo
+func (l *IPList) EndCycle() error {
+	if state := l.client.BreakerState(); state != BreakerClosed {
+		return fmt.Errorf("circuit breaker %s tras el ciclo: la API está fallando", state)
+	}
+	return nil
+}
+
+// SyncIP agrega ip a la lista (como /64 para IPv6, el prefijo más específico que
+// aceptan las listas) y elimina los elementos gestionados anteriores de la misma
+// familia. Retorna nil si la lista ya estaba al día.
+func (l *IPList) SyncIP(ctx context.Context, recordType, ip string) (*provider.IPListChange, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	change, staleIDs, err := l.plan(ctx, recordType, ip)
+	if err != nil {
+		return nil, err
+	}
+	if change == nil {
+		l.previous[recordType] = ip
+		return nil, nil
+	}
+
+	// Primero agregar la IP nueva para que la lista nunca quede sin la IP actual
+	if change.Added != "" {
+		if err := l.client.CreateListItems(ctx, l.listID, []ListItem{{IP: change.Added, Comment: l.comment}}); err != nil {
+			return nil, fmt.Errorf("error agregando %s a la lista %s: %w", change.Added, l.name, err)
+		}
+	}
+	if len(staleIDs) > 0 {
+		if err := l.client.DeleteListItems(ctx, l.listID, staleIDs); err != nil {
+			return nil, fmt.Errorf("error eliminando IPs anteriores de la lista %s: %w", l.name, err)
+		}
+	}
+
+	l.previous[recordType] = ip
+	return change, nil
+}
+
+// PlanIP retorna el cambio que SyncIP aplicaría, sin modificar la lista
+func (l *IPList) PlanIP(ctx context.Context, recordType, ip string) (*provider.IPListChange, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	change, _, err := l.plan(ctx, recordType, ip)
+	return change, err
+}
+
+// plan compara la lista con la IP deseada; retorna el cambio y los IDs a eliminar (requiere mu)
+func (l *IPList) plan(ctx context.Context, recordType, ip string) (*provider.IPListChange, []string, error) {
+	entry, err := listEntry(ip)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := l.resolve(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	items, err := l.client.ListItems(ctx, l.listID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listando elementos de la lista %s: %w", l.name, err)
+	}
+
+	var previousEntry string
+	if previous := l.previous[recordType]; previous != "" && previous != ip {
+		previousEntry, _ = listEntry(previous)
+	}
+
+	present := false
+	change := &provider.IPListChange{List: l.name, RecordType: recordType}
+	var staleIDs []string
+	for _, item := range items {
+		itemEntry := normalizeListEntry(item.IP)
+		if itemEntry == entry {
+			present = true
+			continue
+		}
+		if !sameFamily(itemEntry, entry) {
+			continue
+		}
+		if item.Comment == l.comment || (previousEntry != "" && itemEntry == previousEntry) {
+			staleIDs = append(staleIDs, item.ID)
+			change.Removed = append(change.Removed, itemEntry)
+		}
+	}
+	if !present {
+		change.Added = entry
+	}
+
+	if change.Added == "" && len(change.Removed) == 0 {
+		return nil, nil, nil
+	}
+	return change, staleIDs, nil
+}
+
+// resolve busca el ID de la lista por nombre (requiere mu)
+func (l *IPList) resolve(ctx context.Context) error {
+	if l.listID != "" {
+		return nil
+	}
+
+	lists, err := l.client.ListLists(ctx)
+	if err != nil {
+		return fmt.Errorf("error obteniendo listas de la cuenta: %w", err)
+	}
+	for _, list := range lists {
+		if list.Name != l.name {
+			continue
+		}
+		if list.Kind != "ip" {
+			return fmt.Errorf("la lista %s es de tipo %s, no de IPs: %w", l.name, list.Kind, ErrValidation)
+		}
+		l.listID = list.ID
+		return nil
+	}
+	return fmt.Errorf("la lista %s no existe en la cuenta: %w", l.name, ErrNotFound)
+}
+
+// listEntry convierte una IP al formato de la lista: la IPv4 tal cual y la IPv6
+// como su prefijo /64
+func listEntry(ip string) (string, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "", fmt.Errorf("IP inválida: %q", ip)
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.String(), nil
+	}
+	network := net.IPNet{IP: parsed.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}
+	return network.String(), nil
+}
+
+// normalizeListEntry normaliza un elemento de la lista (IP o CIDR) para compararlo
+func normalizeListEntry(entry string) string {
+	if _, network, err := net.ParseCIDR(entry); err == nil {
+		if ones, bits := network.Mask.Size(); bits == 32 && ones == 32 {
+			return network.IP.String()
+		}
+		return network.String()
+	}
+	if parsed := net.ParseIP(entry); parsed != nil {
+		return parsed.String()
+	}
+	return strings.TrimSpace(entry)
+}
+
+// sameFamily indica si dos elementos de la lista son de la misma familia de IP
+func sameFamily(a, b string) bool {
+	return strings.Contains(a, ":") == strings.Contains(b, ":")
+}
@@ -0,0 +1,346 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// Permiso de edición DNS: grupo de permisos de un API Token ("DNS Write") y permiso
+// del usuario sobre la zona (API Key + Email)
+const (
+	permissionGroupDNSWrite     = "4755a26eedb94da69e1066d98aa820be"
+	permissionGroupDNSWriteName = "DNS Write"
+	permissionDNSEdit           = "#dns_records:edit"
+)
+
+// Prefijos de los recursos de las políticas de un API Token
+const (
+	resourceZonePrefix    = "com.cloudflare.api.account.zone."
+	resourceAccountPrefix = "com.cloudflare.api.account."
+)
+
+// Prefijo del registro de prueba con el que se verifica el permiso de edición DNS
+const probeRecordPrefix = "_orgmdns-preflight."
+
+var _ provider.Preflighter = (*Provider)(nil)
+
+// TokenStatus es el resultado de /user/tokens/verify
+type TokenStatus struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"` // active, disabled, expired
+	ExpiresOn string `json:"expires_on"`
+	NotBefore string `json:"not_before"`
+}
+
+type TokenStatusResponse struct {
+	Result  TokenStatus      `json:"result"`
+	Success bool             `json:"success"`
+	Errors  []APIErrorDetail `json:"errors"`
+}
+
+// Token es un API Token con sus políticas (/user/tokens/{token_id})
+type Token struct {
+	ID       string        `json:"id"`
+	Name     string        `json:"name"`
+	Status   string        `json:"status"`
+	Policies []TokenPolicy `json:"policies"`
+}
+
+// TokenPolicy es una política del token: permite (allow) o niega (deny) los grupos
+// de permisos sobre los recursos. Los recursos de cuenta anidan los de sus zonas.
+type TokenPolicy struct {
+	ID               string                     `json:"id"`
+	Effect           string                     `json:"effect"`
+	Resources        map[string]json.RawMessage `json:"resources"`
+	PermissionGroups []PermissionGroup          `json:"permission_groups"`
+}
+
+// PermissionGroup es un grupo de permisos de una política
+type PermissionGroup struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type TokenResponse struct {
+	Result  Token            `json:"result"`
+	Success bool             `json:"success"`
+	Errors  []APIErrorDetail `json:"errors"`
+}
+
+// User es el usuario dueño de una API Key legacy (/user)
+type User struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+}
+
+type UserResponse struct {
+	Result  User             `json:"result"`
+	Success bool             `json:"success"`
+	Errors  []APIErrorDetail `json:"errors"`
+}
+
+// UsesToken indica si el cliente se autentica con API Token (Bearer) y no con
+// API Key + Email
+func (c *Client) UsesToken() bool {
+	return c.apiEmail == ""
+}
+
+// VerifyToken verifica el API Token (GET /user/tokens/verify)
+func (c *Client) VerifyToken(ctx context.Context) (*TokenStatus, error) {
+	url := fmt.Sprintf("%s/user/tokens/verify", c.baseURL)
+
+	var tokenResp TokenStatusResponse
+	if err := c.do(ctx, "GET", url, nil, &tokenResp); err != nil {
+		return nil, err
+	}
+
+	return &tokenResp.Result, nil
+}
+
+// GetToken obtiene el API Token con sus políticas (GET /user/tokens/{token_id}).
+// Requiere que el token tenga el permiso API Tokens Read.
+func (c *Client) GetToken(ctx context.Context, tokenID string) (*Token, error) {
+	url := fmt.Sprintf("%s/user/tokens/%s", c.baseURL, tokenID)
+
+	var tokenResp TokenResponse
+	if err := c.do(ctx, "GET", url, nil, &tokenResp); err != nil {
+		return nil, err
+	}
+
+	return &tokenResp.Result, nil
+}
+
+// GetUser obtiene el usuario de la API Key legacy (GET /user)
+func (c *Client) GetUser(ctx context.Context) (*User, error) {
+	url := fmt.Sprintf("%s/user", c.baseURL)
+
+	var userResp UserResponse
+	if err := c.do(ctx, "GET", url, nil, &userResp); err != nil {
+		return nil, err
+	}
+
+	return &userResp.Result, nil
+}
+
+// SetWriteProbe activa la comprobación del permiso de edición DNS con un registro de
+// prueba cuando no se puede deducir de las políticas del token (CF_PREFLIGHT_PROBE)
+func (p *Provider) SetWriteProbe(enabled bool) {
+	p.writeProbe = enabled
+}
+
+// grants es lo que se sabe de los permisos de las credenciales
+type grants struct {
+	policies []TokenPolicy // políticas del API Token
+	known    bool          // se leyeron las políticas
+	err      error         // por qué no se pudieron leer
+}
+
+// Preflight verifica antes del primer ciclo que las credenciales son válidas, que
+// la zona de cada registro es accesible y que se pueden editar sus registros DNS.
+// Con un API Token el permiso se deduce de sus políticas y con API Key + Email de
+// los permisos del usuario sobre la zona; solo con SetWriteProbe se crea un registro
+// de prueba. Los problemas de credenciales o permisos se retornan envueltos en
+// ErrAuth y las zonas inexistentes en ErrNotFound, con un diagnóstico de la causa
+// probable. Si el permiso de edición no se pudo determinar, el error envuelve
+// ErrUnverified.
+func (p *Provider) Preflight(ctx context.Context, recordNames []string) error {
+	g, err := p.verifyCredentials(ctx)
+	if err != nil {
+		return err
+	}
+
+	var unverified []error
+	checked := make(map[string]bool)
+	for _, name := range recordNames {
+		zone, err := p.zones.Resolve(ctx, name)
+		if err != nil {
+			if IsNotFound(err) {
+				return fmt.Errorf("%w: la zona no existe en la cuenta o las credenciales no tienen permiso Zone:Read sobre ella", err)
+			}
+			return err
+		}
+		if checked[zone.ID] {
+			continue
+		}
+		checked[zone.ID] = true
+
+		details, err := p.client.GetZone(ctx, zone.ID)
+		if err != nil {
+			if IsAuthError(err) || IsNotFound(err) {
+				return fmt.Errorf("la zona %s (%s) no es accesible con estas credenciales: %w", zone.Name, zone.ID, err)
+			}
+			return fmt.Errorf("error obteniendo zona %s: %w", zone.Name, err)
+		}
+
+		if err := p.checkDNSEdit(ctx, details, g); err != nil {
+			if !errors.Is(err, ErrUnverified) {
+				return err
+			}
+			unverified = append(unverified, err)
+		}
+	}
+	return errors.Join(unverified...)
+}
+
+// checkDNSEdit verifica que las credenciales puedan editar registros DNS de la zona
+func (p *Provider) checkDNSEdit(ctx context.Context, zone *Zone, g grants) error {
+	denied := fmt.Errorf("las credenciales no pueden editar registros DNS en la zona %s (falta el permiso Zone → DNS → Edit): %w", zone.Name, ErrAuth)
+
+	var reason error
+	switch {
+	case !p.client.UsesToken():
+		// Con API Key + Email las credenciales son las del usuario
+		if len(zone.Permissions) > 0 {
+			if hasPermission(zone.Permissions, permissionDNSEdit) {
+				return nil
+			}
+			return denied
+		}
+		reason = errors.New("la zona no informa los permisos del usuario")
+	case g.known:
+		if tokenAllowsDNSEdit(g.policies, zone) {
+			return nil
+		}
+		return denied
+	default:
+		reason = g.err
+	}
+
+	if !p.writeProbe {
+		return fmt.Errorf("no se pudo verificar el permiso de edición DNS en la zona %s (CF_PREFLIGHT_PROBE=true lo comprueba con un registro de prueba): %w: %w", zone.Name, ErrUnverified, reason)
+	}
+	if err := p.probeDNSEdit(ctx, zone); err != nil {
+		if IsAuthError(err) {
+			return fmt.Errorf("las credenciales no pueden editar registros DNS en la zona %s (falta el permiso Zone → DNS → Edit): %w", zone.Name, err)
+		}
+		return fmt.Errorf("no se pudo verificar el permiso de edición DNS en la zona %s: %w: %w", zone.Name, ErrUnverified, err)
+	}
+	return nil
+}
+
+// probeDNSEdit verifica el permiso de edición DNS creando un registro A con
+// contenido inválido: sin permiso la API responde 403 y con permiso rechaza el
+// contenido sin crear nada. Si aun así se creó, se borra.
+func (p *Provider) probeDNSEdit(ctx context.Context, zone *Zone) error {
+	record, err := p.client.CreateDNSRecord(ctx, zone.ID, DNSRecordCreateRequest{
+		Type:    "A",
+		Name:    probeRecordPrefix + zone.Name,
+		Content: "orgmdns-preflight",
+		TTL:     1,
+	})
+	switch {
+	case err == nil:
+		if err := p.client.DeleteDNSRecord(ctx, zone.ID, record.ID); err != nil {
+			return fmt.Errorf("se creó el registro de prueba %s y no se pudo borrar: %w", record.Name, err)
+		}
+		return nil
+	case IsAuthError(err):
+		return err
+	case IsValidation(err):
+		return nil
+	}
+	return err
+}
+
+// tokenAllowsDNSEdit indica si las políticas del token permiten editar registros
+// DNS de la zona. Una política deny que cubre la zona prevalece sobre las allow.
+func tokenAllowsDNSEdit(policies []TokenPolicy, zone *Zone) bool {
+	allowed := false
+	for _, policy := range policies {
+		if !hasDNSWrite(policy.PermissionGroups) || !coversZone(policy.Resources, zone) {
+			continue
+		}
+		if policy.Effect == "deny" {
+			return false
+		}
+		allowed = true
+	}
+	return allowed
+}
+
+// hasDNSWrite indica si los grupos de permisos incluyen DNS Write
+func hasDNSWrite(groups []PermissionGroup) bool {
+	for _, group := range groups {
+		if group.ID == permissionGroupDNSWrite || group.Name == permissionGroupDNSWriteName {
+			return true
+		}
+	}
+	return false
+}
+
+// coversZone indica si los recursos de una política incluyen la zona: la zona
+// concreta, todas las zonas o las zonas de su cuenta
+func coversZone(resources map[string]json.RawMessage, zone *Zone) bool {
+	for key, value := range resources {
+		if id, ok := strings.CutPrefix(key, resourceZonePrefix); ok {
+			if id == "*" || id == zone.ID {
+				return true
+			}
+			continue
+		}
+		if id, ok := strings.CutPrefix(key, resourceAccountPrefix); ok && (id == "*" || id == zone.Account.ID) {
+			var nested map[string]json.RawMessage
+			if json.Unmarshal(value, &nested) == nil && coversZone(nested, zone) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verifyCredentials valida el API Token o la API Key legacy con un diagnóstico claro
+// y, con un API Token, lee sus políticas
+func (p *Provider) verifyCredentials(ctx context.Context) (grants, error) {
+	if !p.client.UsesToken() {
+		if _, err := p.client.GetUser(ctx); err != nil {
+			if IsAuthError(err) {
+				return grants{}, fmt.Errorf("API_KEY o API_EMAIL inválidos para el método legacy (API Key + Email); verifica la Global API Key y el email de la cuenta: %w", err)
+			}
+			return grants{}, fmt.Errorf("error verificando la API Key: %w", err)
+		}
+		return grants{}, nil
+	}
+
+	status, err := p.client.VerifyToken(ctx)
+	if err != nil {
+		if IsAuthError(err) {
+			return grants{}, fmt.Errorf("API_KEY no es un API Token válido (si es una Global API Key, configura API_EMAIL para usar el método legacy): %w", err)
+		}
+		return grants{}, fmt.Errorf("error verificando el API Token: %w", err)
+	}
+	if status.Status != "active" {
+		return grants{}, fmt.Errorf("el API Token está en estado %q (vence: %s): %w", status.Status, orDash(status.ExpiresOn), ErrAuth)
+	}
+
+	token, err := p.client.GetToken(ctx, status.ID)
+	if err != nil {
+		if IsAuthError(err) {
+			err = fmt.Errorf("el token no puede leer sus propias políticas (permiso User → API Tokens → Read): %w", err)
+		}
+		return grants{err: err}, nil
+	}
+	return grants{policies: token.Policies, known: true}, nil
+}
+
+// hasPermission indica si la lista de permisos incluye el indicado
+func hasPermission(permissions []string, permission string) bool {
+	for _, p := range permissions {
+		if strings.EqualFold(p, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// orDash retorna "-" para valores vacíos
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,154 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// ProviderName es el nombre del proveedor en DNS_PROVIDER y ZONE_PROVIDERS
+const ProviderName = "cloudflare"
+
+var (
+	_ provider.DNSProvider = (*Provider)(nil)
+	_ provider.CycleAware  = (*Provider)(nil)
+)
+
+// Provider adapta el cliente de Cloudflare a provider.DNSProvider
+type Provider struct {
+	client     *Client
+	zones      *ZoneResolver
+	writeProbe bool // verificar el permiso de edición DNS con un registro de prueba
+}
+
+// NewProvider crea el proveedor de Cloudflare sobre un cliente y su resolvedor de zonas
+func NewProvider(client *Client, zones *ZoneResolver) *Provider {
+	return &Provider{client: client, zones: zones}
+}
+
+// Client retorna el cliente de Cloudflare subyacente
+func (p *Provider) Client() *Client {
+	return p.client
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// Capabilities: Cloudflare soporta todos los ajustes de registro
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{TTL: true, Proxied: true, Comment: true, Tags: true, ListZone: true}
+}
+
+func (p *Provider) ResolveZone(ctx context.Context, recordName string) (*provider.Zone, error) {
+	zone, err := p.zones.Resolve(ctx, recordName)
+	if err != nil {
+		return nil, err
+	}
+	return &provider.Zone{ID: zone.ID, Name: zone.Name}, nil
+}
+
+func (p *Provider) ListRecords(ctx context.Context, zone provider.Zone, filter provider.ListFilter) ([]provider.Record, error) {
+	records, err := p.client.ListDNSRecords(ctx, zone.ID, ListDNSRecordsFilter{Name: filter.Name, Type: filter.Type})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]provider.Record, 0, len(records))
+	for _, record := range records {
+		result = append(result, toProviderRecord(record))
+	}
+	return result, nil
+}
+
+func (p *Provider) GetRecord(ctx context.Context, zone provider.Zone, name, recordType string) (*provider.Record, error) {
+	records, err := p.client.ListDNSRecords(ctx, zone.ID, ListDNSRecordsFilter{Name: name, Type: recordType})
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if normalizeName(record.Name) == normalizeName(name) && record.Type == recordType {
+			result := toProviderRecord(record)
+			return &result, nil
+		}
+	}
+	return nil, fmt.Errorf("registro %s %s: %w", recordType, name, provider.ErrNotFound)
+}
+
+func (p *Provider) CreateRecord(ctx context.Context, zone provider.Zone, record provider.Record) (*provider.Record, error) {
+	created, err := p.client.CreateDNSRecord(ctx, zone.ID, createRequest(record))
+	if err != nil {
+		return nil, err
+	}
+	result := toProviderRecord(*created)
+	return &result, nil
+}
+
+func (p *Provider) UpdateRecord(ctx context.Context, zone provider.Zone, current provider.Record, update provider.RecordUpdate) (*provider.Record, error) {
+	updated, err := p.client.UpdateDNSRecord(ctx, zone.ID, current.ID, updateRequest(update))
+	if err != nil {
+		return nil, err
+	}
+	result := toProviderRecord(*updated)
+	return &result, nil
+}
+
+func (p *Provider) DeleteRecord(ctx context.Context, zone provider.Zone, record provider.Record) error {
+	return p.client.DeleteDNSRecord(ctx, zone.ID, record.ID)
+}
+
+// BeginCycle reinicia el presupuesto de reintentos y falla si el circuit breaker está abierto
+func (p *Provider) BeginCycle() error {
+	p.client.BeginCycle()
+	if state := p.client.BreakerState(); state == BreakerOpen {
+		return fmt.Errorf("circuit breaker %s: %w", state, provider.ErrUnavailable)
+	}
+	return nil
+}
+
+// EndCycle reporta si el circuit breaker quedó abierto o semiabierto tras el ciclo
+func (p *Provider) EndCycle() error {
+	if state := p.client.BreakerState(); state != BreakerClosed {
+		return fmt.Errorf("circuit breaker %s tras el ciclo: la API está fallando", state)
+	}
+	return nil
+}
+
+// createRequest construye el cuerpo de creación de un registro
+func createRequest(record provider.Record) DNSRecordCreateRequest {
+	return DNSRecordCreateRequest{
+		Type:    record.Type,
+		Name:    record.Name,
+		Content: record.Content,
+		TTL:     record.TTL,
+		Proxied: record.Proxied,
+		Comment: record.Comment,
+		Tags:    record.Tags,
+	}
+}
+
+// updateRequest construye el cuerpo del PATCH de un registro
+func updateRequest(update provider.RecordUpdate) DNSRecordUpdateRequest {
+	return DNSRecordUpdateRequest{
+		Content: update.Content,
+		TTL:     update.TTL,
+		Proxied: update.Proxied,
+		Comment: update.Comment,
+		Tags:    update.Tags,
+	}
+}
+
+// toProviderRecord convierte un registro de la API al tipo común
+func toProviderRecord(record DNSRecord) provider.Record {
+	return provider.Record{
+		ID:      record.ID,
+		Type:    record.Type,
+		Name:    record.Name,
+		Content: record.Content,
+		TTL:     record.TTL,
+		Proxied: record.Proxied,
+		Comment: record.Comment,
+		Tags:    record.Tags,
+	}
+}
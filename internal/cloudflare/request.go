@@ -0,0 +1,347 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// Tiempo máximo de cada petición si el contexto no trae deadline
+const requestTimeout = 30 * time.Second
+
+var (
+	// ErrCircuitOpen se retorna sin llamar a la API mientras el circuit breaker está abierto
+	ErrCircuitOpen = fmt.Errorf("circuit breaker abierto: %w", provider.ErrUnavailable)
+	// ErrRetryBudgetExhausted se retorna cuando se agotaron los reintentos del ciclo
+	ErrRetryBudgetExhausted = errors.New("presupuesto de reintentos del ciclo agotado")
+)
+
+// RetryPolicy controla los reintentos y el circuit breaker del cliente
+type RetryPolicy struct {
+	MaxRetries       int           // reintentos por petición
+	BaseDelay        time.Duration // espera inicial del backoff exponencial
+	MaxDelay         time.Duration // espera máxima entre intentos (incluye Retry-After)
+	CycleBudget      int           // reintentos permitidos por ciclo (0 = sin límite)
+	BreakerThreshold int           // fallos consecutivos que abren el circuito (0 = desactivado)
+	BreakerCooldown  time.Duration // tiempo que el circuito permanece abierto
+}
+
+// DefaultRetryPolicy retorna la política de reintentos por defecto
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:       3,
+		BaseDelay:        1 * time.Second,
+		MaxDelay:         60 * time.Second,
+		CycleBudget:      10,
+		BreakerThreshold: 5,
+		BreakerCooldown:  5 * time.Minute,
+	}
+}
+
+// BreakerState es el estado del circuit breaker
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // funcionamiento normal
+	BreakerOpen                         // se rechazan peticiones hasta que pase el cooldown
+	BreakerHalfOpen                     // se permite una petición de prueba
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "abierto"
+	case BreakerHalfOpen:
+		return "semiabierto"
+	default:
+		return "cerrado"
+	}
+}
+
+// breaker guarda el estado del circuit breaker y el presupuesto del ciclo
+type breaker struct {
+	mu               sync.Mutex
+	policy           RetryPolicy
+	state            BreakerState
+	consecutiveFails int
+	openedAt         time.Time
+	probing          bool // hay una petición de prueba en curso (semiabierto)
+	retriesUsed      int
+}
+
+// SetRetryPolicy reemplaza la política de reintentos del cliente
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+	c.breaker.policy = policy
+}
+
+// currentPolicy retorna una copia de la política vigente
+func (b *breaker) currentPolicy() RetryPolicy {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.policy
+}
+
+// BeginCycle reinicia el presupuesto de reintentos; se llama al inicio de cada ciclo
+func (c *Client) BeginCycle() {
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+	c.breaker.retriesUsed = 0
+}
+
+// BreakerState retorna el estado actual del circuit breaker
+func (c *Client) BreakerState() BreakerState {
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+	return c.breaker.currentState()
+}
+
+// currentState pasa de abierto a semiabierto cuando termina el cooldown (requiere mu)
+func (b *breaker) currentState() BreakerState {
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.policy.BreakerCooldown {
+		b.state = BreakerHalfOpen
+	}
+	return b.state
+}
+
+// allow indica si se puede hacer una petición según el circuit breaker. En
+// semiabierto solo se admite una petición de prueba hasta que record la resuelva.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.currentState() {
+	case BreakerOpen:
+		return false
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+	}
+	return true
+}
+
+// abandon libera la petición de prueba sin resultado (contexto cancelado)
+func (b *breaker) abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// takeRetry consume un reintento del presupuesto del ciclo
+func (b *breaker) takeRetry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.policy.CycleBudget > 0 && b.retriesUsed >= b.policy.CycleBudget {
+		return false
+	}
+	b.retriesUsed++
+	return true
+}
+
+// record registra el resultado de una petición. Solo los fallos transitorios
+// (red, 429, 5xx) cuentan para abrir el circuito.
+func (b *breaker) record(transientFailure bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+
+	if !transientFailure {
+		b.consecutiveFails = 0
+		b.state = BreakerClosed
+		return
+	}
+
+	b.consecutiveFails++
+	if b.state == BreakerHalfOpen || (b.policy.BreakerThreshold > 0 && b.consecutiveFails >= b.policy.BreakerThreshold) {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// apiEnvelope contiene los campos comunes de todas las respuestas de la API
+type apiEnvelope struct {
+	Success bool             `json:"success"`
+	Errors  []APIErrorDetail `json:"errors"`
+}
+
+// do ejecuta una petición a la API de Cloudflare y decodifica la respuesta en out.
+// Reintenta errores de red y 5xx con backoff exponencial y jitter, respeta
+// Retry-After en 429, consume el presupuesto del ciclo y alimenta el circuit breaker.
+// Un POST no es idempotente (Cloudflare pudo haberlo aplicado): solo se reintenta
+// en 429 o si la petición no llegó a enviarse (ver retrySafe).
+func (c *Client) do(ctx context.Context, method, url string, payload interface{}, out interface{}) error {
+	var jsonData []byte
+	if payload != nil {
+		var err error
+		jsonData, err = json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("error serializando request: %w", err)
+		}
+	}
+
+	policy := c.breaker.currentPolicy()
+
+	for attempt := 0; ; attempt++ {
+		if !c.breaker.allow() {
+			return ErrCircuitOpen
+		}
+
+		body, wait, err := c.doOnce(ctx, method, url, jsonData)
+		if err == nil {
+			c.breaker.record(false)
+			return decodeResponse(body, out)
+		}
+
+		// wait < 0 indica un error definitivo (4xx, respuesta inválida): no se reintenta.
+		// Una cancelación del contexto tampoco se reintenta ni cuenta como fallo de la API.
+		if ctx.Err() != nil {
+			c.breaker.abandon()
+			return err
+		}
+		if wait < 0 {
+			c.breaker.record(false)
+			return err
+		}
+
+		c.breaker.record(true)
+
+		if attempt >= policy.MaxRetries {
+			return err
+		}
+		if method == http.MethodPost && !retrySafe(err) {
+			return err
+		}
+		if wait == 0 {
+			wait = backoff(policy, attempt)
+		}
+		if wait > policy.MaxDelay {
+			return fmt.Errorf("%w (Retry-After de %v excede la espera máxima)", err, wait)
+		}
+		if !c.breaker.takeRetry() {
+			return fmt.Errorf("%w: %w", ErrRetryBudgetExhausted, err)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (cancelado esperando reintento: %w)", err, ctx.Err())
+		}
+	}
+}
+
+// doOnce hace un intento. Retorna el cuerpo si fue exitoso; en caso de error retorna
+// la espera sugerida (0 = usar backoff, >0 = Retry-After) o -1 si no se debe reintentar.
+func (c *Client) doOnce(ctx context.Context, method, url string, jsonData []byte) ([]byte, time.Duration, error) {
+	var reqBody io.Reader
+	if jsonData != nil {
+		reqBody = bytes.NewReader(jsonData)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return nil, -1, fmt.Errorf("error creando request: %w", err)
+	}
+
+	c.setAuthHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error haciendo request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After")), newAPIError(resp, body)
+	case resp.StatusCode >= 500:
+		return nil, 0, newAPIError(resp, body)
+	case resp.StatusCode != http.StatusOK:
+		return nil, -1, newAPIError(resp, body)
+	}
+
+	// Una respuesta 200 también puede indicar error con success=false
+	var envelope apiEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, -1, fmt.Errorf("error parseando respuesta: %w", err)
+	}
+	if !envelope.Success {
+		return nil, -1, newAPIError(resp, body)
+	}
+
+	return body, 0, nil
+}
+
+// retrySafe indica si una petición no idempotente se puede reintentar tras el error:
+// un rate limit (Cloudflare no la procesó) o un fallo al conectar (no se envió)
+func retrySafe(err error) bool {
+	if errors.Is(err, ErrRateLimited) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// decodeResponse decodifica una respuesta exitosa en out
+func decodeResponse(body []byte, out interface{}) error {
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error parseando respuesta: %w", err)
+	}
+	return nil
+}
+
+// backoff calcula la espera exponencial con jitter igual (entre la mitad y el total
+// del retardo) para el intento indicado
+func backoff(policy RetryPolicy, attempt int) time.Duration {
+	delay := policy.BaseDelay << attempt
+	if delay <= 0 || delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	// Jitter: espera aleatoria entre la mitad y el total
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// parseRetryAfter interpreta el header Retry-After (segundos o fecha HTTP)
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
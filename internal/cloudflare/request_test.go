@@ -0,0 +1,278 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		attempt int
+		delay   time.Duration // retardo antes del jitter
+	}{
+		{attempt: 0, delay: 100 * time.Millisecond},
+		{attempt: 1, delay: 200 * time.Millisecond},
+		{attempt: 3, delay: 800 * time.Millisecond},
+		{attempt: 4, delay: time.Second},  // tope MaxDelay
+		{attempt: 70, delay: time.Second}, // desbordamiento del desplazamiento
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.attempt), func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				got := backoff(policy, tt.attempt)
+				if got < tt.delay/2 || got > tt.delay {
+					t.Fatalf("backoff = %v, want entre %v y %v", got, tt.delay/2, tt.delay)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{name: "segundos", value: "30", min: 30 * time.Second, max: 30 * time.Second},
+		{name: "vacío", value: ""},
+		{name: "cero", value: "0"},
+		{name: "negativo", value: "-5"},
+		{name: "inválido", value: "pronto"},
+		{name: "fecha futura", value: time.Now().Add(2 * time.Minute).UTC().Format(http.TimeFormat), min: 100 * time.Second, max: 2 * time.Minute},
+		{name: "fecha pasada", value: time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value); got < tt.min || got > tt.max {
+				t.Errorf("parseRetryAfter(%q) = %v, want entre %v y %v", tt.value, got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestBreakerTransitions(t *testing.T) {
+	b := &breaker{policy: RetryPolicy{BreakerThreshold: 3, BreakerCooldown: time.Hour}}
+
+	// Cerrado: los fallos no transitorios reinician la cuenta
+	b.record(true)
+	b.record(true)
+	b.record(false)
+	b.record(true)
+	b.record(true)
+	if b.state != BreakerClosed || !b.allow() {
+		t.Fatalf("estado = %v, want cerrado", b.state)
+	}
+
+	// El fallo que alcanza el umbral abre el circuito
+	b.record(true)
+	if b.state != BreakerOpen || b.allow() {
+		t.Fatalf("estado = %v, want abierto sin admitir peticiones", b.state)
+	}
+
+	// Pasado el cooldown queda semiabierto y admite una sola petición de prueba
+	b.openedAt = time.Now().Add(-2 * time.Hour)
+	if !b.allow() {
+		t.Fatal("semiabierto: la petición de prueba fue rechazada")
+	}
+	if b.state != BreakerHalfOpen {
+		t.Fatalf("estado = %v, want semiabierto", b.state)
+	}
+	if b.allow() {
+		t.Fatal("semiabierto: se admitió una segunda petición con la prueba en curso")
+	}
+
+	// Una prueba fallida vuelve a abrir el circuito aunque no alcance el umbral
+	b.record(true)
+	if b.state != BreakerOpen || b.allow() {
+		t.Fatalf("estado = %v, want abierto tras la prueba fallida", b.state)
+	}
+
+	// Una prueba abandonada (contexto cancelado) libera el turno sin cambiar el estado
+	b.openedAt = time.Now().Add(-2 * time.Hour)
+	if !b.allow() {
+		t.Fatal("semiabierto: la petición de prueba fue rechazada")
+	}
+	b.abandon()
+	if b.state != BreakerHalfOpen || !b.allow() {
+		t.Fatalf("estado = %v, want semiabierto admitiendo otra prueba", b.state)
+	}
+
+	// Una prueba exitosa cierra el circuito
+	b.record(false)
+	if b.state != BreakerClosed || b.consecutiveFails != 0 || !b.allow() || !b.allow() {
+		t.Fatalf("estado = %v, fallos = %d, want cerrado", b.state, b.consecutiveFails)
+	}
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	b := &breaker{policy: RetryPolicy{BreakerThreshold: 0}}
+	for i := 0; i < 100; i++ {
+		b.record(true)
+	}
+	if b.state != BreakerClosed || !b.allow() {
+		t.Errorf("estado = %v, want cerrado con el breaker desactivado", b.state)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	b := &breaker{policy: RetryPolicy{CycleBudget: 2}}
+	if !b.takeRetry() || !b.takeRetry() || b.takeRetry() {
+		t.Fatal("el presupuesto de 2 reintentos no se respetó")
+	}
+
+	c := &Client{breaker: breaker{policy: RetryPolicy{CycleBudget: 2}, retriesUsed: 2}}
+	c.BeginCycle()
+	if !c.breaker.takeRetry() {
+		t.Error("BeginCycle no reinició el presupuesto")
+	}
+}
+
+// newTestClient crea un cliente contra la API de prueba con esperas cortas
+func newTestClient(t *testing.T, policy RetryPolicy, handler http.HandlerFunc) (*Client, string) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c := NewClient("", "cf-token", "")
+	c.baseURL = server.URL
+	c.SetRetryPolicy(policy)
+	return c, server.URL + "/zones/z1/dns_records"
+}
+
+func TestDoRetries(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, CycleBudget: 10, BreakerThreshold: 5, BreakerCooldown: time.Hour}
+
+	// respond retorna un handler que responde los status indicados en orden
+	respond := func(calls *int, statuses ...int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if got := r.Header.Get("Authorization"); got != "Bearer cf-token" {
+				t.Errorf("Authorization = %q", got)
+			}
+			status := statuses[len(statuses)-1]
+			if *calls < len(statuses) {
+				status = statuses[*calls]
+			}
+			*calls++
+			w.WriteHeader(status)
+			if status == http.StatusOK {
+				fmt.Fprint(w, `{"success":true,"errors":[],"result":[]}`)
+				return
+			}
+			fmt.Fprintf(w, `{"success":false,"errors":[{"code":%d,"message":"%s"}]}`, status, http.StatusText(status))
+		}
+	}
+
+	tests := []struct {
+		name      string
+		method    string
+		statuses  []int
+		wantCalls int
+		wantIs    error // nil = éxito
+	}{
+		{name: "5xx y luego éxito", method: http.MethodGet, statuses: []int{503, 502, 200}, wantCalls: 3},
+		{name: "5xx persistente", method: http.MethodGet, statuses: []int{500}, wantCalls: 3, wantIs: ErrUnavailable},
+		{name: "4xx no se reintenta", method: http.MethodGet, statuses: []int{403}, wantCalls: 1, wantIs: ErrAuth},
+		{name: "POST con 5xx no se reintenta", method: http.MethodPost, statuses: []int{500, 200}, wantCalls: 1, wantIs: ErrUnavailable},
+		{name: "POST con 429 se reintenta", method: http.MethodPost, statuses: []int{429, 200}, wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			c, url := newTestClient(t, policy, respond(&calls, tt.statuses...))
+
+			err := c.do(context.Background(), tt.method, url, nil, nil)
+			if calls != tt.wantCalls {
+				t.Errorf("intentos = %d, want %d", calls, tt.wantCalls)
+			}
+			if tt.wantIs == nil {
+				if err != nil {
+					t.Errorf("error inesperado: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantIs) {
+				t.Errorf("error = %v, want %v", err, tt.wantIs)
+			}
+		})
+	}
+}
+
+func TestDoRetryAfterExceedsMaxDelay(t *testing.T) {
+	var calls int
+	c, url := newTestClient(t, RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second}, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"success":false,"errors":[{"code":10429,"message":"rate limited"}]}`)
+	})
+
+	err := c.do(context.Background(), http.MethodGet, url, nil, nil)
+	if calls != 1 || !errors.Is(err, ErrRateLimited) || !strings.Contains(err.Error(), "excede la espera máxima") {
+		t.Errorf("intentos = %d, error = %v", calls, err)
+	}
+}
+
+func TestDoRetryBudgetExhausted(t *testing.T) {
+	var calls int
+	c, url := newTestClient(t, RetryPolicy{MaxRetries: 5, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, CycleBudget: 1}, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	err := c.do(context.Background(), http.MethodGet, url, nil, nil)
+	if calls != 2 || !errors.Is(err, ErrRetryBudgetExhausted) || !errors.Is(err, ErrUnavailable) {
+		t.Errorf("intentos = %d, error = %v", calls, err)
+	}
+}
+
+func TestDoCircuitOpen(t *testing.T) {
+	var calls int
+	c, url := newTestClient(t, RetryPolicy{BreakerThreshold: 2, BreakerCooldown: time.Hour}, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	for i := 0; i < 2; i++ {
+		if err := c.do(context.Background(), http.MethodGet, url, nil, nil); !errors.Is(err, ErrUnavailable) || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("petición %d: error = %v", i, err)
+		}
+	}
+	if c.BreakerState() != BreakerOpen {
+		t.Fatalf("estado = %v, want abierto", c.BreakerState())
+	}
+
+	// Con el circuito abierto no se llama a la API
+	err := c.do(context.Background(), http.MethodGet, url, nil, nil)
+	if !errors.Is(err, ErrCircuitOpen) || !errors.Is(err, ErrUnavailable) || calls != 2 {
+		t.Errorf("intentos = %d, error = %v", calls, err)
+	}
+}
+
+func TestDoSuccessFalse(t *testing.T) {
+	c, url := newTestClient(t, DefaultRetryPolicy(), func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("CF-Ray", "8a1b2c3d4e5f-MIA")
+		fmt.Fprint(w, `{"success":false,"errors":[{"code":81057,"message":"Record already exists."}]}`)
+	})
+
+	err := c.do(context.Background(), http.MethodGet, url, nil, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusOK || apiErr.RequestID != "8a1b2c3d4e5f-MIA" || !errors.Is(err, ErrValidation) {
+		t.Errorf("APIError = %+v", *apiErr)
+	}
+}
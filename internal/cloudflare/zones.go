@@ -0,0 +1,157 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tiempo que se recuerda que un nombre no es una zona antes de volver a consultarlo
+const zoneNegativeCacheTTL = 1 * time.Hour
+
+type Zone struct {
+	ID      string      `json:"id"`
+	Name    string      `json:"name"`
+	Status  string      `json:"status"`
+	Account ZoneAccount `json:"account"`
+	// Permisos del usuario sobre la zona (p. ej. "#dns_records:edit"). Son los del
+	// dueño de las credenciales, no los de un API Token.
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// ZoneAccount es la cuenta dueña de la zona
+type ZoneAccount struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type ZonesResponse struct {
+	Result  []Zone           `json:"result"`
+	Success bool             `json:"success"`
+	Errors  []APIErrorDetail `json:"errors"`
+}
+
+type ZoneResponse struct {
+	Result  Zone             `json:"result"`
+	Success bool             `json:"success"`
+	Errors  []APIErrorDetail `json:"errors"`
+}
+
+// ListZones obtiene las zonas con el nombre exacto indicado (GET /zones?name=)
+func (c *Client) ListZones(ctx context.Context, name string) ([]Zone, error) {
+	query := url.Values{}
+	query.Set("name", name)
+	reqURL := fmt.Sprintf("%s/zones?%s", c.baseURL, query.Encode())
+
+	var zonesResp ZonesResponse
+	if err := c.do(ctx, "GET", reqURL, nil, &zonesResp); err != nil {
+		return nil, err
+	}
+
+	return zonesResp.Result, nil
+}
+
+// GetZone obtiene una zona por su ID (GET /zones/{zone_id})
+func (c *Client) GetZone(ctx context.Context, zoneID string) (*Zone, error) {
+	url := fmt.Sprintf("%s/zones/%s", c.baseURL, zoneID)
+
+	var zoneResp ZoneResponse
+	if err := c.do(ctx, "GET", url, nil, &zoneResp); err != nil {
+		return nil, err
+	}
+
+	return &zoneResp.Result, nil
+}
+
+// ZoneResolver asocia nombres de registro con su zona de Cloudflare por el sufijo
+// más largo. Las zonas configuradas explícitamente tienen prioridad; el resto se
+// descubre con /zones?name= y se guarda en caché.
+type ZoneResolver struct {
+	client *Client
+
+	mu         sync.Mutex
+	zones      map[string]Zone      // nombre de zona -> zona (explícitas y descubiertas)
+	notZones   map[string]time.Time // nombres que no son zona -> expiración de la caché
+	pendingIDs []string             // IDs configurados sin nombre, se resuelven al primer uso
+}
+
+// NewZoneResolver crea un resolvedor de zonas. explicit mapea nombre de zona a ID;
+// ids son IDs configurados sin nombre (por ejemplo ZONE_ID) cuyo nombre se consulta a la API.
+func NewZoneResolver(client *Client, explicit map[string]string, ids []string) *ZoneResolver {
+	zr := &ZoneResolver{
+		client:     client,
+		zones:      make(map[string]Zone),
+		notZones:   make(map[string]time.Time),
+		pendingIDs: ids,
+	}
+	for name, id := range explicit {
+		name = normalizeName(name)
+		zr.zones[name] = Zone{ID: id, Name: name}
+	}
+	return zr
+}
+
+// Resolve retorna la zona que contiene el nombre de registro indicado
+func (zr *ZoneResolver) Resolve(ctx context.Context, recordName string) (*Zone, error) {
+	zr.mu.Lock()
+	defer zr.mu.Unlock()
+
+	if err := zr.loadPendingIDs(ctx); err != nil {
+		return nil, err
+	}
+
+	name := normalizeName(recordName)
+	labels := strings.Split(name, ".")
+
+	// Primero las zonas conocidas (explícitas o en caché), por sufijo más largo
+	for i := 0; i < len(labels)-1; i++ {
+		if zone, ok := zr.zones[strings.Join(labels[i:], ".")]; ok {
+			return &zone, nil
+		}
+	}
+
+	// Descubrir con la API, del sufijo más largo al más corto
+	for i := 0; i < len(labels)-1; i++ {
+		candidate := strings.Join(labels[i:], ".")
+		if expires, ok := zr.notZones[candidate]; ok && time.Now().Before(expires) {
+			continue
+		}
+
+		zones, err := zr.client.ListZones(ctx, candidate)
+		if err != nil {
+			return nil, fmt.Errorf("error buscando zona %s: %w", candidate, err)
+		}
+		if len(zones) == 0 {
+			zr.notZones[candidate] = time.Now().Add(zoneNegativeCacheTTL)
+			continue
+		}
+
+		zone := zones[0]
+		zr.zones[normalizeName(zone.Name)] = zone
+		return &zone, nil
+	}
+
+	return nil, fmt.Errorf("no se encontró zona de Cloudflare para %s: %w", recordName, ErrNotFound)
+}
+
+// loadPendingIDs consulta el nombre de las zonas configuradas solo por ID
+func (zr *ZoneResolver) loadPendingIDs(ctx context.Context) error {
+	for len(zr.pendingIDs) > 0 {
+		id := zr.pendingIDs[0]
+		zone, err := zr.client.GetZone(ctx, id)
+		if err != nil {
+			return fmt.Errorf("error obteniendo zona %s: %w", id, err)
+		}
+		zr.zones[normalizeName(zone.Name)] = *zone
+		zr.pendingIDs = zr.pendingIDs[1:]
+	}
+	return nil
+}
+
+// normalizeName pasa un nombre DNS a minúsculas y sin punto final
+func normalizeName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
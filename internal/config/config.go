@@ -9,11 +9,32 @@ import (
 )
 
 type Config struct {
-	// Cloudflare
+	// Proveedores DNS
+	DNSProvider   string            // proveedor por defecto (DNS_PROVIDER)
+	ZoneProviders map[string]string // Opcional: zona -> proveedor (ZONE_PROVIDERS)
+
+	// Cloudflare (requerido si algún registro usa Cloudflare)
 	AccountID string
 	APIKey    string
-	ZoneID    string
-	APIEmail  string // Opcional: para autenticación con API Key (método legacy)
+	ZoneID    string            // Opcional: zona única (compatibilidad), su nombre se consulta a la API
+	Zones     map[string]string // Opcional: nombre de zona -> ID explícito (ZONES)
+	APIEmail  string            // Opcional: para autenticación con API Key (método legacy)
+
+	// RFC 2136 (requerido si algún registro usa rfc2136)
+	RFC2136 RFC2136Config
+
+	// PowerDNS (requerido si algún registro usa powerdns)
+	PowerDNS PowerDNSConfig
+
+	// dyndns2 (requerido si algún registro usa dyndns2)
+	DynDNS2 DynDNS2Config
+
+	// Route 53 (requerido si algún registro usa route53)
+	Route53 Route53Config
+
+	// DigitalOcean y Hetzner DNS (requeridos si algún registro los usa)
+	DigitalOcean DigitalOceanConfig
+	Hetzner      HetznerConfig
 
 	// Email
 	Email         string
@@ -26,27 +47,67 @@ type Config struct {
 	// App
 	SleepTime   int // minutos
 	RecordNames []string
+	Records     []Record // RECORD_NAMES con las familias a gestionar por registro
 	Debug       bool
+
+	// Creación de registros inexistentes (opcional)
+	CreateMissing bool
+
+	// Reintentos y circuit breaker de la API de Cloudflare
+	CFMaxRetries       int
+	CFRetryBudget      int // reintentos por ciclo, 0 = sin límite
+	CFBreakerThreshold int // fallos consecutivos, 0 = desactivado
+	CFBreakerCooldown  int // minutos
+
+	// Listas de IPs de la cuenta de Cloudflare con la IP pública (opcional)
+	CFIPLists       []string // nombres de las listas (CF_IP_LISTS)
+	CFIPListComment string   // comentario que marca los elementos gestionados
+
+	// Apagado
+	ShutdownGrace  int  // segundos para terminar el trabajo en curso
+	ShutdownNotify bool // enviar correo al detenerse
+
+	// Verificar credenciales y permisos de los proveedores al iniciar
+	Preflight bool
+	// Verificar el permiso de edición DNS de Cloudflare con un registro de prueba
+	CFPreflightProbe bool
+
+	// Fuentes de IP pública en orden (IP_SOURCES), vacío = STUN y HTTP por defecto
+	IPSources []IPSourceConfig
+	// Consenso: consultar todas las fuentes en paralelo y exigir IPQuorum coincidencias
+	IPConsensus bool
+	IPQuorum    int
+	// Tiempo máximo de cada consulta de IP (IP_SOURCE_TIMEOUT)
+	IPSourceTimeout time.Duration
+	// Comparar la IP pública con la dirección WAN del router (UPnP, NAT-PMP/PCP)
+	IPDoubleNATCheck bool
+
+	// Dry-run: calcular el plan de un ciclo sin aplicar cambios ni enviar correos
+	DryRun     bool
+	PlanFormat string // salida del plan: "" (solo log), "table" o "json"
 }
 
 func Load() (*Config, error) {
 	cfg := &Config{}
 
-	// Cloudflare
-	cfg.AccountID = os.Getenv("ACCOUNT_ID")
-	if cfg.AccountID == "" {
-		return nil, fmt.Errorf("ACCOUNT_ID es requerido")
+	// Proveedores DNS
+	if err := loadProviders(cfg); err != nil {
+		return nil, err
 	}
 
+	// Cloudflare (se valida más abajo, cuando se conocen los registros)
+	cfg.AccountID = os.Getenv("ACCOUNT_ID")
 	cfg.APIKey = os.Getenv("API_KEY")
-	if cfg.APIKey == "" {
-		return nil, fmt.Errorf("API_KEY es requerido")
-	}
 
+	// Zonas: ZONE_ID y ZONES son opcionales; las zonas no configuradas
+	// se descubren automáticamente por el nombre de cada registro
 	cfg.ZoneID = os.Getenv("ZONE_ID")
-	if cfg.ZoneID == "" {
-		return nil, fmt.Errorf("ZONE_ID es requerido")
+
+	zones, err := parseZones(os.Getenv("ZONES"))
+	if err != nil {
+		return nil, fmt.Errorf("ZONES inválido: %w", err)
 	}
+	cfg.Zones = zones
 
 	// API Email (opcional, solo para método legacy API Key)
 	// Si no está configurado, intentar usar EMAIL como fallback (como en Python)
@@ -98,6 +159,12 @@ func Load() (*Config, error) {
 		cfg.SleepTime = sleepTime
 	}
 
+	// Valores por defecto de cada registro (RECORD_TYPES, RECORD_TTL, RECORD_PROXIED, ...)
+	defaults, err := loadRecordDefaults()
+	if err != nil {
+		return nil, err
+	}
+
 	recordNamesStr := os.Getenv("RECORD_NAMES")
 	if recordNamesStr == "" {
 		return nil, fmt.Errorf("RECORD_NAMES es requerido")
@@ -106,24 +173,169 @@ func Load() (*Config, error) {
 	// Parsear RECORD_NAMES separados por comas y hacer trim
 	parts := strings.Split(recordNamesStr, ",")
 	cfg.RecordNames = make([]string, 0, len(parts))
+	cfg.Records = make([]Record, 0, len(parts))
 	for _, part := range parts {
 		trimmed := strings.TrimSpace(part)
-		if trimmed != "" {
-			cfg.RecordNames = append(cfg.RecordNames, trimmed)
+		if trimmed == "" {
+			continue
 		}
+		record, err := parseRecord(trimmed, defaults)
+		if err != nil {
+			return nil, fmt.Errorf("RECORD_NAMES inválido (%s): %w", trimmed, err)
+		}
+		cfg.RecordNames = append(cfg.RecordNames, record.Name)
+		cfg.Records = append(cfg.Records, record)
 	}
 
 	if len(cfg.RecordNames) == 0 {
 		return nil, fmt.Errorf("RECORD_NAMES debe contener al menos un registro")
 	}
 
+	// Listas de IPs de Cloudflare (usan ACCOUNT_ID)
+	for _, name := range strings.Split(os.Getenv("CF_IP_LISTS"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			cfg.CFIPLists = append(cfg.CFIPLists, name)
+		}
+	}
+	cfg.CFIPListComment = strings.TrimSpace(os.Getenv("CF_IP_LIST_COMMENT"))
+	if cfg.CFIPListComment == "" {
+		cfg.CFIPListComment = "orgmdns"
+	}
+
+	// Las credenciales de Cloudflare solo son requeridas si algún registro o lista lo usa
+	if cfg.UsesProvider(ProviderCloudflare) || len(cfg.CFIPLists) > 0 {
+		if cfg.AccountID == "" {
+			return nil, fmt.Errorf("ACCOUNT_ID es requerido")
+		}
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("API_KEY es requerido")
+		}
+	}
+
+	// Configuración de los demás proveedores
+	if err := loadRFC2136(cfg); err != nil {
+		return nil, err
+	}
+	if err := loadPowerDNS(cfg); err != nil {
+		return nil, err
+	}
+	if err := loadDynDNS2(cfg); err != nil {
+		return nil, err
+	}
+	if err := loadRoute53(cfg); err != nil {
+		return nil, err
+	}
+	if err := loadDigitalOcean(cfg); err != nil {
+		return nil, err
+	}
+	if err := loadHetzner(cfg); err != nil {
+		return nil, err
+	}
+
 	// Debug
 	cfg.Debug = os.Getenv("DEBUG") == "true"
 
+	// Creación de registros inexistentes
+	cfg.CreateMissing = os.Getenv("CREATE_MISSING") == "true"
+
+	// Reintentos y circuit breaker de la API de Cloudflare
+	if cfg.CFMaxRetries, err = intEnv("CF_MAX_RETRIES", 3, 0); err != nil {
+		return nil, err
+	}
+	if cfg.CFRetryBudget, err = intEnv("CF_RETRY_BUDGET", 10, 0); err != nil {
+		return nil, err
+	}
+	if cfg.CFBreakerThreshold, err = intEnv("CF_BREAKER_THRESHOLD", 5, 0); err != nil {
+		return nil, err
+	}
+	if cfg.CFBreakerCooldown, err = intEnv("CF_BREAKER_COOLDOWN", 5, 1); err != nil {
+		return nil, err
+	}
+
+	// Apagado
+	if cfg.ShutdownGrace, err = intEnv("SHUTDOWN_GRACE", 30, 0); err != nil {
+		return nil, err
+	}
+	cfg.ShutdownNotify = os.Getenv("SHUTDOWN_NOTIFY") == "true"
+
+	// Verificación de credenciales y permisos al iniciar (activada por defecto)
+	cfg.Preflight = os.Getenv("PREFLIGHT") != "false"
+	cfg.CFPreflightProbe = os.Getenv("CF_PREFLIGHT_PROBE") == "true"
+
+	// Fuentes de IP pública
+	if err := loadIPSources(cfg); err != nil {
+		return nil, err
+	}
+
+	// Dry-run y formato del plan
+	cfg.DryRun = os.Getenv("DRY_RUN") == "true"
+	if err := cfg.SetPlanFormat(os.Getenv("PLAN_FORMAT")); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
+// intEnv lee una variable entera con valor por defecto y mínimo permitido
+func intEnv(name string, def, min int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s debe ser un número entero: %w", name, err)
+	}
+	if n < min {
+		return 0, fmt.Errorf("%s debe ser mayor o igual que %d", name, min)
+	}
+	return n, nil
+}
+
+// parseZones interpreta ZONES con el formato "zona=id,zona=id"
+func parseZones(value string) (map[string]string, error) {
+	zones := make(map[string]string)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, id, ok := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		id = strings.TrimSpace(id)
+		if !ok || name == "" || id == "" {
+			return nil, fmt.Errorf("entrada %q debe tener el formato zona=id", part)
+		}
+		zones[name] = id
+	}
+	return zones, nil
+}
+
+// SetPlanFormat valida y asigna el formato de salida del plan (PLAN_FORMAT o --plan-format)
+func (c *Config) SetPlanFormat(format string) error {
+	format = strings.ToLower(strings.TrimSpace(format))
+	switch format {
+	case "", "table", "json":
+		c.PlanFormat = format
+		return nil
+	}
+	return fmt.Errorf("PLAN_FORMAT inválido %q (use table o json)", format)
+}
+
+// Route53WaitDuration retorna la espera máxima a INSYNC de Route 53 (0 = no esperar)
+func (c *Config) Route53WaitDuration() time.Duration {
+	if !c.Route53.WaitInSync {
+		return 0
+	}
+	return time.Duration(c.Route53.WaitTimeout) * time.Second
+}
+
 // SleepDuration retorna el tiempo de espera como time.Duration
 func (c *Config) SleepDuration() time.Duration {
 	return time.Duration(c.SleepTime) * time.Minute
 }
+
+// ShutdownGraceDuration retorna el periodo de gracia del apagado como time.Duration
+func (c *Config) ShutdownGraceDuration() time.Duration {
+	return time.Duration(c.ShutdownGrace) * time.Second
+}
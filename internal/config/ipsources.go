@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fuentes de IP pública soportadas en IP_SOURCES
+const (
+	IPSourceSTUN      = "stun"
+	IPSourceHTTP      = "http"
+	IPSourceDNS       = "dns"
+	IPSourceInterface = "interface"
+	IPSourceCommand   = "cmd"
+	IPSourceUPnP      = "upnp"
+	IPSourceNATPMP    = "natpmp"
+)
+
+// IPSourceConfig es una fuente de IP_SOURCES.
+//
+// Cada entrada tiene el formato `tipo[:destino][;clave=valor]...`, por ejemplo:
+//
+//	stun:stun.l.google.com:19302
+//	http:https://api.ipify.org;types=A;timeout=3
+//	dns
+//	dns:127.0.0.1:5353|127.0.0.2;method=google
+//	interface:eth0;types=AAAA
+//	cmd:/usr/local/bin/wan-ip
+//	upnp
+//	natpmp:192.168.1.1
+type IPSourceConfig struct {
+	Kind    string        // stun, http, dns, interface, cmd, upnp o natpmp
+	Target  string        // servidor, URL, resolvers (separados por |), interfaz, comando, descripción UPnP o gateway (vacío = por defecto)
+	Types   []string      // familias que atiende, vacío = ambas
+	Timeout time.Duration // tiempo máximo de la consulta
+	Method  string        // dns: opendns (por defecto) o google
+}
+
+// Métodos de la fuente dns (opción method=)
+const (
+	DNSMethodOpenDNS = "opendns"
+	DNSMethodGoogle  = "google"
+)
+
+// Resolvers retorna los servidores de una fuente dns (Target separado por |)
+func (s IPSourceConfig) Resolvers() []string {
+	var resolvers []string
+	for _, resolver := range strings.Split(s.Target, "|") {
+		if resolver = strings.TrimSpace(resolver); resolver != "" {
+			resolvers = append(resolvers, resolver)
+		}
+	}
+	return resolvers
+}
+
+// loadIPSources lee IP_SOURCES (vacío = fuentes por defecto), IP_SOURCE_TIMEOUT, el
+// modo consenso (IP_CONSENSUS, IP_QUORUM) y la detección de doble NAT (IP_DOUBLE_NAT_CHECK)
+func loadIPSources(cfg *Config) error {
+	timeout, err := intEnv("IP_SOURCE_TIMEOUT", 5, 1)
+	if err != nil {
+		return err
+	}
+
+	cfg.IPConsensus = os.Getenv("IP_CONSENSUS") == "true"
+	if cfg.IPQuorum, err = intEnv("IP_QUORUM", 2, 1); err != nil {
+		return err
+	}
+	defaultTimeout := time.Duration(timeout) * time.Second
+	cfg.IPSourceTimeout = defaultTimeout
+	cfg.IPDoubleNATCheck = os.Getenv("IP_DOUBLE_NAT_CHECK") == "true"
+
+	for _, part := range strings.Split(os.Getenv("IP_SOURCES"), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		source, err := parseIPSource(part, defaultTimeout)
+		if err != nil {
+			return fmt.Errorf("IP_SOURCES inválido (%s): %w", part, err)
+		}
+		cfg.IPSources = append(cfg.IPSources, source)
+	}
+	return nil
+}
+
+// parseIPSource interpreta una entrada de IP_SOURCES
+func parseIPSource(spec string, defaultTimeout time.Duration) (IPSourceConfig, error) {
+	parts := strings.Split(spec, ";")
+	kind, target, _ := strings.Cut(strings.TrimSpace(parts[0]), ":")
+	source := IPSourceConfig{
+		Kind:    strings.ToLower(strings.TrimSpace(kind)),
+		Target:  strings.TrimSpace(target),
+		Timeout: defaultTimeout,
+	}
+
+	switch source.Kind {
+	case IPSourceSTUN, IPSourceHTTP, IPSourceDNS, IPSourceUPnP, IPSourceNATPMP:
+	case IPSourceInterface, IPSourceCommand:
+		if source.Target == "" {
+			return IPSourceConfig{}, fmt.Errorf("%s requiere un destino (%s:valor)", source.Kind, source.Kind)
+		}
+	default:
+		return IPSourceConfig{}, fmt.Errorf("tipo de fuente desconocido: %s (válidos: stun, http, dns, interface, cmd, upnp, natpmp)", source.Kind)
+	}
+
+	for _, opt := range parts[1:] {
+		opt = strings.TrimSpace(opt)
+		if opt == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(opt, "=")
+		if !ok {
+			return IPSourceConfig{}, fmt.Errorf("opción sin valor: %s", opt)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "types":
+			types, err := parseRecordTypes(value)
+			if err != nil {
+				return IPSourceConfig{}, err
+			}
+			source.Types = types
+		case "timeout":
+			seconds, err := strconv.Atoi(value)
+			if err != nil || seconds < 1 {
+				return IPSourceConfig{}, fmt.Errorf("timeout debe ser un número entero de segundos mayor que 0")
+			}
+			source.Timeout = time.Duration(seconds) * time.Second
+		case "method":
+			if source.Kind != IPSourceDNS {
+				return IPSourceConfig{}, fmt.Errorf("method solo se admite en fuentes dns")
+			}
+			switch method := strings.ToLower(value); method {
+			case DNSMethodOpenDNS, DNSMethodGoogle:
+				source.Method = method
+			default:
+				return IPSourceConfig{}, fmt.Errorf("method desconocido: %s (válidos: opendns, google)", value)
+			}
+		default:
+			return IPSourceConfig{}, fmt.Errorf("opción desconocida: %s", key)
+		}
+	}
+
+	return source, nil
+}
@@ -0,0 +1,341 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Proveedores DNS soportados
+const (
+	ProviderCloudflare   = "cloudflare"
+	ProviderRFC2136      = "rfc2136"
+	ProviderPowerDNS     = "powerdns"
+	ProviderDynDNS2      = "dyndns2"
+	ProviderRoute53      = "route53"
+	ProviderDigitalOcean = "digitalocean"
+	ProviderHetzner      = "hetzner"
+)
+
+// knownProviders son los nombres válidos en DNS_PROVIDER y ZONE_PROVIDERS
+var knownProviders = []string{
+	ProviderCloudflare, ProviderRFC2136, ProviderPowerDNS, ProviderDynDNS2,
+	ProviderRoute53, ProviderDigitalOcean, ProviderHetzner,
+}
+
+// RFC2136Config es la configuración del proveedor RFC 2136 (DNS UPDATE con TSIG)
+type RFC2136Config struct {
+	Server        string // host o host:puerto del servidor autoritativo
+	Network       string // udp o tcp
+	TSIGKey       string // nombre de la clave TSIG, vacío = sin firma
+	TSIGSecret    string // secreto TSIG en base64
+	TSIGAlgorithm string // hmac-sha256 o hmac-sha512
+	TTL           int    // TTL de los registros con TTL automático
+	Prerequisites bool   // exigir prerrequisitos en cada actualización
+}
+
+// loadRFC2136 lee la configuración RFC2136_* (solo se valida si algún registro usa el proveedor)
+func loadRFC2136(cfg *Config) error {
+	rc := RFC2136Config{
+		Server:        strings.TrimSpace(os.Getenv("RFC2136_SERVER")),
+		Network:       strings.ToLower(strings.TrimSpace(os.Getenv("RFC2136_NET"))),
+		TSIGKey:       strings.TrimSpace(os.Getenv("RFC2136_TSIG_KEY")),
+		TSIGSecret:    strings.TrimSpace(os.Getenv("RFC2136_TSIG_SECRET")),
+		TSIGAlgorithm: strings.ToLower(strings.TrimSpace(os.Getenv("RFC2136_TSIG_ALGORITHM"))),
+		Prerequisites: os.Getenv("RFC2136_PREREQUISITES") != "false",
+	}
+	if rc.Network == "" {
+		rc.Network = "udp"
+	}
+	if rc.TSIGAlgorithm == "" {
+		rc.TSIGAlgorithm = "hmac-sha256"
+	}
+
+	var err error
+	if rc.TTL, err = intEnv("RFC2136_TTL", 300, 1); err != nil {
+		return err
+	}
+	cfg.RFC2136 = rc
+
+	if !cfg.UsesProvider(ProviderRFC2136) {
+		return nil
+	}
+	if rc.Server == "" {
+		return fmt.Errorf("RFC2136_SERVER es requerido")
+	}
+	if rc.Network != "udp" && rc.Network != "tcp" {
+		return fmt.Errorf("RFC2136_NET inválido: %q (válidos: udp, tcp)", rc.Network)
+	}
+	if rc.TSIGAlgorithm != "hmac-sha256" && rc.TSIGAlgorithm != "hmac-sha512" {
+		return fmt.Errorf("RFC2136_TSIG_ALGORITHM inválido: %q (válidos: hmac-sha256, hmac-sha512)", rc.TSIGAlgorithm)
+	}
+	if rc.TSIGKey != "" && rc.TSIGSecret == "" {
+		return fmt.Errorf("RFC2136_TSIG_SECRET es requerido si se configura RFC2136_TSIG_KEY")
+	}
+	return nil
+}
+
+// PowerDNSConfig es la configuración del proveedor PowerDNS (API HTTP)
+type PowerDNSConfig struct {
+	APIURL   string // URL de la API, por ejemplo http://pdns:8081
+	APIKey   string // X-API-Key
+	ServerID string // servidor de la API, normalmente "localhost"
+	TTL      int    // TTL de los registros con TTL automático
+}
+
+// loadPowerDNS lee la configuración PDNS_* (solo se valida si algún registro usa el proveedor)
+func loadPowerDNS(cfg *Config) error {
+	pc := PowerDNSConfig{
+		APIURL:   strings.TrimSpace(os.Getenv("PDNS_API_URL")),
+		APIKey:   os.Getenv("PDNS_API_KEY"),
+		ServerID: strings.TrimSpace(os.Getenv("PDNS_SERVER_ID")),
+	}
+	if pc.ServerID == "" {
+		pc.ServerID = "localhost"
+	}
+
+	var err error
+	if pc.TTL, err = intEnv("PDNS_TTL", 300, 1); err != nil {
+		return err
+	}
+	cfg.PowerDNS = pc
+
+	if !cfg.UsesProvider(ProviderPowerDNS) {
+		return nil
+	}
+	if pc.APIURL == "" {
+		return fmt.Errorf("PDNS_API_URL es requerido")
+	}
+	if pc.APIKey == "" {
+		return fmt.Errorf("PDNS_API_KEY es requerido")
+	}
+	return nil
+}
+
+// DynDNS2Config es la configuración del proveedor dyndns2 (No-IP, DynDNS, ...)
+type DynDNS2Config struct {
+	URL       string // URL base del servicio (sin /nic/update)
+	Username  string
+	Password  string
+	UserAgent string // el protocolo exige "Empresa Producto/versión email"
+}
+
+// loadDynDNS2 lee la configuración DYNDNS2_* (solo se valida si algún registro usa el proveedor)
+func loadDynDNS2(cfg *Config) error {
+	dc := DynDNS2Config{
+		URL:       strings.TrimSpace(os.Getenv("DYNDNS2_URL")),
+		Username:  os.Getenv("DYNDNS2_USERNAME"),
+		Password:  os.Getenv("DYNDNS2_PASSWORD"),
+		UserAgent: strings.TrimSpace(os.Getenv("DYNDNS2_USER_AGENT")),
+	}
+	if dc.URL == "" {
+		dc.URL = "https://dynupdate.no-ip.com"
+	}
+	if dc.UserAgent == "" {
+		dc.UserAgent = "orgmdns/1.0 " + cfg.EmailFrom
+	}
+	cfg.DynDNS2 = dc
+
+	if !cfg.UsesProvider(ProviderDynDNS2) {
+		return nil
+	}
+	if dc.Username == "" {
+		return fmt.Errorf("DYNDNS2_USERNAME es requerido")
+	}
+	if dc.Password == "" {
+		return fmt.Errorf("DYNDNS2_PASSWORD es requerido")
+	}
+	return nil
+}
+
+// Route53Config es la configuración del proveedor AWS Route 53
+type Route53Config struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string            // opcional, credenciales temporales
+	Endpoint        string            // vacío = endpoint público de AWS
+	Region          string            // región de firma SigV4
+	Zones           map[string]string // Opcional: zona -> ID de hosted zone (R53_ZONES)
+	TTL             int               // TTL de los registros con TTL automático
+	WaitInSync      bool              // esperar a que cada cambio esté INSYNC
+	WaitTimeout     int               // segundos máximos de espera a INSYNC
+}
+
+// loadRoute53 lee la configuración R53_* (con AWS_* como alternativa para las
+// credenciales); solo se valida si algún registro usa el proveedor
+func loadRoute53(cfg *Config) error {
+	rc := Route53Config{
+		AccessKeyID:     envOr("R53_ACCESS_KEY_ID", "AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: envOr("R53_SECRET_ACCESS_KEY", "AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    envOr("R53_SESSION_TOKEN", "AWS_SESSION_TOKEN"),
+		Endpoint:        strings.TrimSpace(os.Getenv("R53_ENDPOINT")),
+		Region:          strings.TrimSpace(os.Getenv("R53_REGION")),
+		WaitInSync:      os.Getenv("R53_WAIT_INSYNC") == "true",
+	}
+	if rc.Region == "" {
+		rc.Region = "us-east-1"
+	}
+
+	zones, err := parseZones(os.Getenv("R53_ZONES"))
+	if err != nil {
+		return fmt.Errorf("R53_ZONES inválido: %w", err)
+	}
+	rc.Zones = zones
+
+	if rc.TTL, err = intEnv("R53_TTL", 300, 1); err != nil {
+		return err
+	}
+	if rc.WaitTimeout, err = intEnv("R53_WAIT_TIMEOUT", 120, 1); err != nil {
+		return err
+	}
+	cfg.Route53 = rc
+
+	if !cfg.UsesProvider(ProviderRoute53) {
+		return nil
+	}
+	if rc.AccessKeyID == "" {
+		return fmt.Errorf("R53_ACCESS_KEY_ID (o AWS_ACCESS_KEY_ID) es requerido")
+	}
+	if rc.SecretAccessKey == "" {
+		return fmt.Errorf("R53_SECRET_ACCESS_KEY (o AWS_SECRET_ACCESS_KEY) es requerido")
+	}
+	return nil
+}
+
+// DigitalOceanConfig es la configuración del proveedor DigitalOcean DNS
+type DigitalOceanConfig struct {
+	Token   string // token de la API (Bearer)
+	BaseURL string // vacío = https://api.digitalocean.com/v2
+	TTL     int    // TTL de los registros con TTL automático (mínimo 30)
+}
+
+// loadDigitalOcean lee la configuración DO_* (solo se valida si algún registro usa el proveedor)
+func loadDigitalOcean(cfg *Config) error {
+	dc := DigitalOceanConfig{
+		Token:   strings.TrimSpace(os.Getenv("DO_TOKEN")),
+		BaseURL: strings.TrimSpace(os.Getenv("DO_API_URL")),
+	}
+
+	var err error
+	if dc.TTL, err = intEnv("DO_TTL", 300, 30); err != nil {
+		return err
+	}
+	cfg.DigitalOcean = dc
+
+	if cfg.UsesProvider(ProviderDigitalOcean) && dc.Token == "" {
+		return fmt.Errorf("DO_TOKEN es requerido")
+	}
+	return nil
+}
+
+// HetznerConfig es la configuración del proveedor Hetzner DNS
+type HetznerConfig struct {
+	Token   string // Auth-API-Token
+	BaseURL string // vacío = https://dns.hetzner.com/api/v1
+}
+
+// loadHetzner lee la configuración HETZNER_DNS_* (solo se valida si algún registro usa el proveedor)
+func loadHetzner(cfg *Config) error {
+	hc := HetznerConfig{
+		Token:   strings.TrimSpace(os.Getenv("HETZNER_DNS_TOKEN")),
+		BaseURL: strings.TrimSpace(os.Getenv("HETZNER_DNS_API_URL")),
+	}
+	cfg.Hetzner = hc
+
+	if cfg.UsesProvider(ProviderHetzner) && hc.Token == "" {
+		return fmt.Errorf("HETZNER_DNS_TOKEN es requerido")
+	}
+	return nil
+}
+
+// envOr retorna la primera variable de entorno no vacía
+func envOr(names ...string) string {
+	for _, name := range names {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// loadProviders lee DNS_PROVIDER (proveedor por defecto) y ZONE_PROVIDERS
+// ("zona=proveedor,zona=proveedor") con el proveedor de cada zona
+func loadProviders(cfg *Config) error {
+	cfg.DNSProvider = strings.ToLower(strings.TrimSpace(os.Getenv("DNS_PROVIDER")))
+	if cfg.DNSProvider == "" {
+		cfg.DNSProvider = ProviderCloudflare
+	}
+	if !isKnownProvider(cfg.DNSProvider) {
+		return fmt.Errorf("DNS_PROVIDER inválido: %q (válidos: %s)", cfg.DNSProvider, strings.Join(knownProviders, ", "))
+	}
+
+	cfg.ZoneProviders = make(map[string]string)
+	for _, part := range strings.Split(os.Getenv("ZONE_PROVIDERS"), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		zone, name, ok := strings.Cut(part, "=")
+		zone = normalizeZone(zone)
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || zone == "" || name == "" {
+			return fmt.Errorf("ZONE_PROVIDERS inválido: entrada %q debe tener el formato zona=proveedor", part)
+		}
+		if !isKnownProvider(name) {
+			return fmt.Errorf("ZONE_PROVIDERS inválido: proveedor %q desconocido (válidos: %s)", name, strings.Join(knownProviders, ", "))
+		}
+		cfg.ZoneProviders[zone] = name
+	}
+	return nil
+}
+
+// ProviderFor retorna el proveedor que gestiona el registro y la zona configurada
+// en ZONE_PROVIDERS que lo contiene (sufijo más largo). Si ninguna zona coincide
+// retorna DNS_PROVIDER y zona vacía.
+func (c *Config) ProviderFor(recordName string) (provider, zone string) {
+	labels := strings.Split(normalizeZone(recordName), ".")
+	for i := range labels {
+		candidate := strings.Join(labels[i:], ".")
+		if name, ok := c.ZoneProviders[candidate]; ok {
+			return name, candidate
+		}
+	}
+	return c.DNSProvider, ""
+}
+
+// UsesProvider indica si algún registro configurado usa el proveedor indicado
+func (c *Config) UsesProvider(name string) bool {
+	for _, record := range c.Records {
+		if p, _ := c.ProviderFor(record.Name); p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ProviderZones retorna las zonas de ZONE_PROVIDERS asignadas al proveedor indicado
+func (c *Config) ProviderZones(name string) []string {
+	var zones []string
+	for zone, p := range c.ZoneProviders {
+		if p == name {
+			zones = append(zones, zone)
+		}
+	}
+	sort.Strings(zones)
+	return zones
+}
+
+func isKnownProvider(name string) bool {
+	for _, known := range knownProviders {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeZone pasa un nombre DNS a minúsculas y sin punto final
+func normalizeZone(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
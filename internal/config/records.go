@@ -0,0 +1,202 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Tipos de registro DNS soportados
+const (
+	RecordTypeA    = "A"    // IPv4
+	RecordTypeAAAA = "AAAA" // IPv6
+)
+
+// Record describe un registro configurado en RECORD_NAMES.
+//
+// Cada entrada tiene el formato `nombre[;clave=valor]...`, por ejemplo:
+//
+//	orgmcr.or-gm.com
+//	nas.or-gm.com;types=A|AAAA
+//	v6.or-gm.com;types=AAAA;ttl=300;proxied=false
+//	web.or-gm.com;proxied=true;comment=Servidor web;tags=env:prod|team:infra
+//
+// Los ajustes TTL, Proxied, Comment y Tags son opcionales: si no se configuran
+// (ni por registro ni con RECORD_TTL, RECORD_PROXIED, RECORD_COMMENT o
+// RECORD_TAGS) no se gestionan y se respeta lo que haya en el proveedor.
+type Record struct {
+	Name  string
+	Types []string // familias gestionadas: A, AAAA o ambas
+
+	TTL     int      // segundos, 1 = automático, 0 = no gestionado
+	Proxied *bool    // nil = no gestionado
+	Comment *string  // nil = no gestionado
+	Tags    []string // nil = no gestionado, ordenados
+}
+
+// HasType indica si el registro gestiona el tipo indicado
+func (r Record) HasType(recordType string) bool {
+	for _, t := range r.Types {
+		if t == recordType {
+			return true
+		}
+	}
+	return false
+}
+
+// ManagesType indica si algún registro configurado gestiona el tipo indicado
+func (c *Config) ManagesType(recordType string) bool {
+	for _, record := range c.Records {
+		if record.HasType(recordType) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadRecordDefaults lee los valores por defecto aplicados a todos los registros
+func loadRecordDefaults() (Record, error) {
+	defaults := Record{Types: []string{RecordTypeA}}
+
+	// Tipos de registro por defecto (A, AAAA o ambos separados por |)
+	if value := os.Getenv("RECORD_TYPES"); value != "" {
+		types, err := parseRecordTypes(value)
+		if err != nil {
+			return Record{}, fmt.Errorf("RECORD_TYPES inválido: %w", err)
+		}
+		defaults.Types = types
+	}
+
+	if value := os.Getenv("RECORD_TTL"); value != "" {
+		ttl, err := parseTTL(value)
+		if err != nil {
+			return Record{}, fmt.Errorf("RECORD_TTL inválido: %w", err)
+		}
+		defaults.TTL = ttl
+	}
+
+	if value := os.Getenv("RECORD_PROXIED"); value != "" {
+		proxied, err := strconv.ParseBool(value)
+		if err != nil {
+			return Record{}, fmt.Errorf("RECORD_PROXIED debe ser true o false: %w", err)
+		}
+		defaults.Proxied = &proxied
+	}
+
+	if value := os.Getenv("RECORD_COMMENT"); value != "" {
+		defaults.Comment = &value
+	}
+
+	if value := os.Getenv("RECORD_TAGS"); value != "" {
+		defaults.Tags = parseTags(value)
+	}
+
+	return defaults, nil
+}
+
+// parseRecord interpreta una entrada de RECORD_NAMES partiendo de los valores por defecto
+func parseRecord(spec string, defaults Record) (Record, error) {
+	parts := strings.Split(spec, ";")
+	record := defaults
+	record.Name = strings.TrimSpace(parts[0])
+	if record.Name == "" {
+		return Record{}, fmt.Errorf("nombre vacío")
+	}
+
+	for _, opt := range parts[1:] {
+		opt = strings.TrimSpace(opt)
+		if opt == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(opt, "=")
+		if !ok {
+			return Record{}, fmt.Errorf("opción sin valor: %s", opt)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "types":
+			types, err := parseRecordTypes(value)
+			if err != nil {
+				return Record{}, err
+			}
+			record.Types = types
+		case "ttl":
+			ttl, err := parseTTL(value)
+			if err != nil {
+				return Record{}, fmt.Errorf("ttl inválido: %w", err)
+			}
+			record.TTL = ttl
+		case "proxied":
+			proxied, err := strconv.ParseBool(value)
+			if err != nil {
+				return Record{}, fmt.Errorf("proxied debe ser true o false: %w", err)
+			}
+			record.Proxied = &proxied
+		case "comment":
+			comment := value
+			record.Comment = &comment
+		case "tags":
+			record.Tags = parseTags(value)
+		default:
+			return Record{}, fmt.Errorf("opción desconocida: %s", key)
+		}
+	}
+
+	return record, nil
+}
+
+// parseRecordTypes interpreta una lista de tipos separados por |
+func parseRecordTypes(value string) ([]string, error) {
+	var types []string
+	seen := make(map[string]bool)
+	for _, t := range strings.Split(value, "|") {
+		t = strings.ToUpper(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if t != RecordTypeA && t != RecordTypeAAAA {
+			return nil, fmt.Errorf("tipo de registro no soportado: %s", t)
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("debe indicar al menos un tipo de registro")
+	}
+	return types, nil
+}
+
+// parseTTL interpreta un TTL en segundos: 1 (automático) o entre 30 y 86400
+func parseTTL(value string) (int, error) {
+	ttl, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("debe ser un número entero: %w", err)
+	}
+	if ttl != 1 && (ttl < 30 || ttl > 86400) {
+		return 0, fmt.Errorf("debe ser 1 (automático) o estar entre 30 y 86400 segundos")
+	}
+	return ttl, nil
+}
+
+// parseTags interpreta una lista de etiquetas separadas por | (la lista vacía
+// "-" indica que el registro no debe tener etiquetas)
+func parseTags(value string) []string {
+	tags := []string{}
+	if strings.TrimSpace(value) == "-" {
+		return tags
+	}
+	for _, tag := range strings.Split(value, "|") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,162 @@
+// Package digitalocean implementa un cliente de la API de DNS de DigitalOcean
+// (/v2/domains/{domain}/records).
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/osmargm1202/orgmdns/internal/httputil"
+)
+
+// Tamaño de página usado al listar registros DNS
+const listPerPage = 200
+
+type Client struct {
+	baseURL string
+	api     httputil.JSONClient
+}
+
+// Domain es un dominio (zona) de DigitalOcean
+type Domain struct {
+	Name string `json:"name"`
+	TTL  int    `json:"ttl"`
+}
+
+// DomainRecord es un registro DNS. Name es relativo al dominio ("@" para el apex).
+type DomainRecord struct {
+	ID   int    `json:"id"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+	Data string `json:"data"`
+	TTL  int    `json:"ttl"`
+}
+
+type DomainResponse struct {
+	Domain Domain `json:"domain"`
+}
+
+type DomainRecordsResponse struct {
+	DomainRecords []DomainRecord `json:"domain_records"`
+	Links         Links          `json:"links"`
+	Meta          Meta           `json:"meta"`
+}
+
+type DomainRecordResponse struct {
+	DomainRecord DomainRecord `json:"domain_record"`
+}
+
+// Links contiene los enlaces de paginación de las respuestas de listado
+type Links struct {
+	Pages struct {
+		Next string `json:"next"`
+	} `json:"pages"`
+}
+
+// Meta contiene el total de elementos de las respuestas de listado
+type Meta struct {
+	Total int `json:"total"`
+}
+
+// DomainRecordRequest es el cuerpo para crear o actualizar un registro
+type DomainRecordRequest struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	Data string `json:"data"`
+	TTL  int    `json:"ttl,omitempty"`
+}
+
+// NewClient crea un cliente con token de acceso. baseURL vacío usa la API pública.
+func NewClient(token, baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = "https://api.digitalocean.com/v2"
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		api: httputil.JSONClient{
+			HTTPClient: &http.Client{},
+			Auth:       func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token) },
+			NewError:   newAPIError,
+		},
+	}
+}
+
+// BaseURL retorna la URL base de la API usada por el cliente
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// GetDomain obtiene un dominio por nombre (GET /domains/{domain})
+func (c *Client) GetDomain(ctx context.Context, name string) (*Domain, error) {
+	reqURL := fmt.Sprintf("%s/domains/%s", c.baseURL, url.PathEscape(name))
+
+	var domainResp DomainResponse
+	if err := c.api.Do(ctx, "GET", reqURL, nil, &domainResp); err != nil {
+		return nil, err
+	}
+	return &domainResp.Domain, nil
+}
+
+// ListDomainRecords obtiene los registros del dominio recorriendo todas las páginas.
+// recordType y name (FQDN) filtran en el servidor si no están vacíos.
+func (c *Client) ListDomainRecords(ctx context.Context, domain, recordType, name string) ([]DomainRecord, error) {
+	var records []DomainRecord
+
+	for page := 1; ; page++ {
+		query := url.Values{}
+		if recordType != "" {
+			query.Set("type", recordType)
+		}
+		if name != "" {
+			query.Set("name", name)
+		}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("per_page", strconv.Itoa(listPerPage))
+		reqURL := fmt.Sprintf("%s/domains/%s/records?%s", c.baseURL, url.PathEscape(domain), query.Encode())
+
+		var recordsResp DomainRecordsResponse
+		if err := c.api.Do(ctx, "GET", reqURL, nil, &recordsResp); err != nil {
+			return nil, err
+		}
+		records = append(records, recordsResp.DomainRecords...)
+
+		// Parar cuando no hay página siguiente
+		if recordsResp.Links.Pages.Next == "" || len(recordsResp.DomainRecords) == 0 {
+			break
+		}
+	}
+
+	return records, nil
+}
+
+// CreateDomainRecord crea un registro en el dominio
+func (c *Client) CreateDomainRecord(ctx context.Context, domain string, createReq DomainRecordRequest) (*DomainRecord, error) {
+	reqURL := fmt.Sprintf("%s/domains/%s/records", c.baseURL, url.PathEscape(domain))
+
+	var recordResp DomainRecordResponse
+	if err := c.api.Do(ctx, "POST", reqURL, createReq, &recordResp); err != nil {
+		return nil, err
+	}
+	return &recordResp.DomainRecord, nil
+}
+
+// UpdateDomainRecord actualiza un registro del dominio (PATCH)
+func (c *Client) UpdateDomainRecord(ctx context.Context, domain string, recordID int, updateReq DomainRecordRequest) (*DomainRecord, error) {
+	reqURL := fmt.Sprintf("%s/domains/%s/records/%d", c.baseURL, url.PathEscape(domain), recordID)
+
+	var recordResp DomainRecordResponse
+	if err := c.api.Do(ctx, "PATCH", reqURL, updateReq, &recordResp); err != nil {
+		return nil, err
+	}
+	return &recordResp.DomainRecord, nil
+}
+
+// DeleteDomainRecord borra un registro del dominio
+func (c *Client) DeleteDomainRecord(ctx context.Context, domain string, recordID int) error {
+	reqURL := fmt.Sprintf("%s/domains/%s/records/%d", c.baseURL, url.PathEscape(domain), recordID)
+	return c.api.Do(ctx, "DELETE", reqURL, nil, nil)
+}
@@ -0,0 +1,192 @@
+package digitalocean
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// newTestProvider levanta la API de prueba con handler y crea el proveedor
+func newTestProvider(t *testing.T, handler http.HandlerFunc) *Provider {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer do-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return NewProvider(NewClient("do-token", server.URL+"/v2/"), []string{"example.com"}, 300)
+}
+
+func TestListRecordsPagination(t *testing.T) {
+	var pages []string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v2/domains/example.com/records" {
+			t.Errorf("petición = %s %s", r.Method, r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("type") != "A" || query.Get("name") != "home.example.com" || query.Get("per_page") != "200" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+		}
+		page := query.Get("page")
+		pages = append(pages, page)
+
+		resp := DomainRecordsResponse{}
+		switch page {
+		case "1":
+			resp.DomainRecords = []DomainRecord{{ID: 11, Type: "A", Name: "home", Data: "203.0.113.1", TTL: 300}}
+			resp.Links.Pages.Next = "https://api.digitalocean.com/v2/domains/example.com/records?page=2"
+		case "2":
+			resp.DomainRecords = []DomainRecord{{ID: 12, Type: "A", Name: "@", Data: "203.0.113.2", TTL: 1800}}
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+
+	zone, err := p.ResolveZone(context.Background(), "home.example.com")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	records, err := p.ListRecords(context.Background(), *zone, provider.ListFilter{Name: "home.example.com", Type: "A"})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	if fmt.Sprint(pages) != "[1 2]" {
+		t.Errorf("páginas = %v, want [1 2]", pages)
+	}
+	want := []provider.Record{
+		{ID: "11", Type: "A", Name: "home.example.com", Content: "203.0.113.1", TTL: 300},
+		{ID: "12", Type: "A", Name: "example.com", Content: "203.0.113.2", TTL: 1800},
+	}
+	if fmt.Sprint(records) != fmt.Sprint(want) {
+		t.Errorf("registros = %+v, want %+v", records, want)
+	}
+}
+
+func TestCreateAndUpdateRecord(t *testing.T) {
+	type call struct {
+		method, path string
+		body         DomainRecordRequest
+	}
+	var calls []call
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		var body DomainRecordRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("cuerpo inválido: %v", err)
+		}
+		calls = append(calls, call{r.Method, r.URL.Path, body})
+		json.NewEncoder(w).Encode(DomainRecordResponse{DomainRecord: DomainRecord{
+			ID: 42, Type: body.Type, Name: body.Name, Data: body.Data, TTL: body.TTL,
+		}})
+	})
+	zone := provider.Zone{ID: "example.com", Name: "example.com"}
+
+	created, err := p.CreateRecord(context.Background(), zone, provider.Record{Type: "A", Name: "home.example.com", Content: "203.0.113.1", TTL: 1})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if created.ID != "42" || created.Name != "home.example.com" || created.TTL != 300 {
+		t.Errorf("registro creado = %+v", *created)
+	}
+
+	content := "203.0.113.9"
+	updated, err := p.UpdateRecord(context.Background(), zone, *created, provider.RecordUpdate{Content: content})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if updated.Content != content {
+		t.Errorf("contenido = %q, want %q", updated.Content, content)
+	}
+
+	want := []call{
+		{http.MethodPost, "/v2/domains/example.com/records", DomainRecordRequest{Type: "A", Name: "home", Data: "203.0.113.1", TTL: 300}},
+		{http.MethodPatch, "/v2/domains/example.com/records/42", DomainRecordRequest{Type: "A", Name: "home", Data: content, TTL: 300}},
+	}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("peticiones = %+v, want %+v", calls, want)
+	}
+
+	if _, err := p.UpdateRecord(context.Background(), zone, provider.Record{ID: "abc"}, provider.RecordUpdate{}); !errors.Is(err, provider.ErrValidation) {
+		t.Errorf("ID inválido: error = %v, want ErrValidation", err)
+	}
+}
+
+func TestAPIErrorMapping(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		body       string
+		wantIs     error
+		wantID     string
+	}{
+		{statusCode: http.StatusUnauthorized, body: `{"id":"unauthorized","message":"Unable to authenticate you"}`, wantIs: provider.ErrAuth, wantID: "unauthorized"},
+		{statusCode: http.StatusForbidden, body: `{"id":"forbidden","message":"You do not have access"}`, wantIs: provider.ErrAuth, wantID: "forbidden"},
+		{statusCode: http.StatusNotFound, body: `{"id":"not_found","message":"The resource you requested could not be found."}`, wantIs: provider.ErrNotFound, wantID: "not_found"},
+		{statusCode: http.StatusUnprocessableEntity, body: `{"id":"unprocessable_entity","message":"Name is invalid"}`, wantIs: provider.ErrValidation, wantID: "unprocessable_entity"},
+		{statusCode: http.StatusTooManyRequests, body: `{"id":"too_many_requests","message":"API Rate limit exceeded."}`, wantIs: provider.ErrRateLimited, wantID: "too_many_requests"},
+		{statusCode: http.StatusBadGateway, body: "<html>Bad Gateway</html>", wantIs: provider.ErrUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-Id", "req-1")
+				w.WriteHeader(tt.statusCode)
+				fmt.Fprint(w, tt.body)
+			})
+
+			_, err := p.client.ListDomainRecords(context.Background(), "example.com", "A", "")
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want *APIError", err)
+			}
+			if apiErr.ID != tt.wantID || apiErr.RequestID != "req-1" || apiErr.Path != "/v2/domains/example.com/records" {
+				t.Errorf("APIError = %+v", *apiErr)
+			}
+			if !errors.Is(err, tt.wantIs) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.wantIs)
+			}
+			for _, other := range []error{provider.ErrAuth, provider.ErrNotFound, provider.ErrValidation, provider.ErrRateLimited, provider.ErrUnavailable} {
+				if other != tt.wantIs && errors.Is(err, other) {
+					t.Errorf("errors.Is(%v, %v) = true", err, other)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveZoneDiscovery(t *testing.T) {
+	var lookups []string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		lookups = append(lookups, r.URL.Path)
+		if r.URL.Path != "/v2/domains/example.org" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"id":"not_found","message":"not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"domain":{"name":"example.org","ttl":1800}}`)
+	})
+
+	zone, err := p.ResolveZone(context.Background(), "a.b.example.org")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if zone.ID != "example.org" {
+		t.Errorf("zona = %+v, want example.org", *zone)
+	}
+	want := "[/v2/domains/a.b.example.org /v2/domains/b.example.org /v2/domains/example.org]"
+	if fmt.Sprint(lookups) != want {
+		t.Errorf("consultas = %v, want %s", lookups, want)
+	}
+
+	// La zona descubierta queda en caché
+	if _, err := p.ResolveZone(context.Background(), "c.example.org"); err != nil || len(lookups) != 3 {
+		t.Errorf("segunda resolución: err = %v, consultas = %d", err, len(lookups))
+	}
+}
@@ -0,0 +1,58 @@
+package digitalocean
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/osmargm1202/orgmdns/internal/httputil"
+)
+
+// APIError es un error retornado por la API de DigitalOcean
+type APIError struct {
+	StatusCode int
+	ID         string // identificador del error (unauthorized, not_found, ...)
+	Message    string
+	RequestID  string
+	Method     string
+	Path       string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("error de API de DigitalOcean: %s %s status %d", e.Method, e.Path, e.StatusCode)
+	if e.ID != "" {
+		msg += fmt.Sprintf(" [%s: %s]", e.ID, e.Message)
+	} else if e.Message != "" {
+		msg += ", body: " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += " (request id " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// Is clasifica el status HTTP en las categorías de internal/provider
+func (e *APIError) Is(target error) bool {
+	return httputil.StatusIs(e.StatusCode, target)
+}
+
+// newAPIError construye un APIError a partir de la respuesta HTTP
+func newAPIError(resp *http.Response, body []byte) error {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+	apiErr.Method, apiErr.Path = httputil.RequestPath(resp)
+
+	var errResp struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Message != "" {
+		apiErr.ID = errResp.ID
+		apiErr.Message = errResp.Message
+	} else {
+		apiErr.Message = httputil.Truncate(string(body), 512)
+	}
+	return apiErr
+}
@@ -0,0 +1,183 @@
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// ProviderName es el nombre del proveedor en DNS_PROVIDER y ZONE_PROVIDERS
+const ProviderName = "digitalocean"
+
+var _ provider.DNSProvider = (*Provider)(nil)
+
+// Provider adapta la API de DNS de DigitalOcean a provider.DNSProvider
+type Provider struct {
+	client     *Client
+	defaultTTL int // TTL usado cuando el registro pide TTL automático (1)
+
+	mu    sync.Mutex
+	zones map[string]bool // dominios configurados o descubiertos
+}
+
+// NewProvider crea el proveedor de DigitalOcean. zones son los dominios configurados
+// en ZONE_PROVIDERS; los demás se descubren con GET /domains/{domain}.
+func NewProvider(client *Client, zones []string, defaultTTL int) *Provider {
+	p := &Provider{
+		client:     client,
+		defaultTTL: defaultTTL,
+		zones:      make(map[string]bool),
+	}
+	for _, zone := range zones {
+		p.zones[normalizeName(zone)] = true
+	}
+	return p
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// Capabilities: solo TTL
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{TTL: true, ListZone: true, DefaultTTL: p.defaultTTL}
+}
+
+// ResolveZone busca el dominio por sufijo más largo entre los conocidos y, si no
+// hay, lo descubre con la API
+func (p *Provider) ResolveZone(ctx context.Context, recordName string) (*provider.Zone, error) {
+	labels := strings.Split(normalizeName(recordName), ".")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := 0; i < len(labels)-1; i++ {
+		if candidate := strings.Join(labels[i:], "."); p.zones[candidate] {
+			return &provider.Zone{ID: candidate, Name: candidate}, nil
+		}
+	}
+
+	for i := 0; i < len(labels)-1; i++ {
+		candidate := strings.Join(labels[i:], ".")
+		domain, err := p.client.GetDomain(ctx, candidate)
+		if provider.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error buscando dominio %s: %w", candidate, err)
+		}
+		name := normalizeName(domain.Name)
+		p.zones[name] = true
+		return &provider.Zone{ID: name, Name: name}, nil
+	}
+
+	return nil, fmt.Errorf("no se encontró dominio de DigitalOcean para %s: %w", recordName, provider.ErrNotFound)
+}
+
+func (p *Provider) ListRecords(ctx context.Context, zone provider.Zone, filter provider.ListFilter) ([]provider.Record, error) {
+	records, err := p.client.ListDomainRecords(ctx, zone.ID, filter.Type, filter.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]provider.Record, 0, len(records))
+	for _, record := range records {
+		result = append(result, toProviderRecord(zone, record))
+	}
+	return result, nil
+}
+
+func (p *Provider) GetRecord(ctx context.Context, zone provider.Zone, name, recordType string) (*provider.Record, error) {
+	records, err := p.ListRecords(ctx, zone, provider.ListFilter{Name: normalizeName(name), Type: recordType})
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if normalizeName(record.Name) == normalizeName(name) && record.Type == recordType {
+			return &record, nil
+		}
+	}
+	return nil, fmt.Errorf("registro %s %s: %w", recordType, name, provider.ErrNotFound)
+}
+
+func (p *Provider) CreateRecord(ctx context.Context, zone provider.Zone, record provider.Record) (*provider.Record, error) {
+	created, err := p.client.CreateDomainRecord(ctx, zone.ID, p.recordRequest(zone, record))
+	if err != nil {
+		return nil, err
+	}
+	result := toProviderRecord(zone, *created)
+	return &result, nil
+}
+
+func (p *Provider) UpdateRecord(ctx context.Context, zone provider.Zone, current provider.Record, update provider.RecordUpdate) (*provider.Record, error) {
+	id, err := strconv.Atoi(current.ID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de registro inválido %q: %w", current.ID, provider.ErrValidation)
+	}
+
+	updated, err := p.client.UpdateDomainRecord(ctx, zone.ID, id, p.recordRequest(zone, provider.ApplyUpdate(current, update)))
+	if err != nil {
+		return nil, err
+	}
+	result := toProviderRecord(zone, *updated)
+	return &result, nil
+}
+
+func (p *Provider) DeleteRecord(ctx context.Context, zone provider.Zone, record provider.Record) error {
+	id, err := strconv.Atoi(record.ID)
+	if err != nil {
+		return fmt.Errorf("ID de registro inválido %q: %w", record.ID, provider.ErrValidation)
+	}
+	return p.client.DeleteDomainRecord(ctx, zone.ID, id)
+}
+
+// recordRequest construye el cuerpo de creación o actualización con el nombre relativo
+func (p *Provider) recordRequest(zone provider.Zone, record provider.Record) DomainRecordRequest {
+	ttl := record.TTL
+	if ttl <= 1 {
+		ttl = p.defaultTTL
+	}
+	return DomainRecordRequest{
+		Type: record.Type,
+		Name: relativeName(record.Name, zone.Name),
+		Data: record.Content,
+		TTL:  ttl,
+	}
+}
+
+// toProviderRecord convierte un registro de la API (nombre relativo) al tipo común
+func toProviderRecord(zone provider.Zone, record DomainRecord) provider.Record {
+	return provider.Record{
+		ID:      strconv.Itoa(record.ID),
+		Type:    record.Type,
+		Name:    absoluteName(record.Name, zone.Name),
+		Content: record.Data,
+		TTL:     record.TTL,
+	}
+}
+
+// relativeName convierte un FQDN al nombre relativo al dominio ("@" para el apex)
+func relativeName(name, zone string) string {
+	name = normalizeName(name)
+	if name == zone {
+		return "@"
+	}
+	return strings.TrimSuffix(name, "."+zone)
+}
+
+// absoluteName convierte un nombre relativo al dominio en FQDN
+func absoluteName(name, zone string) string {
+	if name == "@" || name == "" {
+		return zone
+	}
+	return normalizeName(name) + "." + zone
+}
+
+// normalizeName pasa un nombre DNS a minúsculas y sin punto final
+func normalizeName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
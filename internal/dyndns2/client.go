@@ -0,0 +1,87 @@
+// Package dyndns2 implementa el protocolo de actualización dyndns2
+// (/nic/update?hostname=&myip=) usado por No-IP, DynDNS y servicios compatibles.
+package dyndns2
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/osmargm1202/orgmdns/internal/httputil"
+)
+
+type Client struct {
+	baseURL    string // por ejemplo https://dynupdate.no-ip.com
+	username   string
+	password   string
+	userAgent  string // el protocolo exige identificar el cliente
+	httpClient *http.Client
+}
+
+// Result es una respuesta exitosa: good (IP cambiada) o nochg (IP sin cambios)
+type Result struct {
+	Code string // good o nochg
+	IP   string // IP que quedó asignada al hostname
+}
+
+// NewClient crea un cliente dyndns2 con autenticación básica
+func NewClient(baseURL, username, password, userAgent string) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		username:   username,
+		password:   password,
+		userAgent:  userAgent,
+		httpClient: &http.Client{},
+	}
+}
+
+// Update asigna la IP al hostname (GET /nic/update?hostname=&myip=)
+func (c *Client) Update(ctx context.Context, hostname, ip string) (*Result, error) {
+	query := url.Values{}
+	query.Set("hostname", hostname)
+	query.Set("myip", ip)
+	reqURL := fmt.Sprintf("%s/nic/update?%s", c.baseURL, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creando request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, body, err := httputil.Do(c.httpClient, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseResponse(hostname, resp.StatusCode, string(body))
+}
+
+// parseResponse interpreta la primera línea de la respuesta ("good 1.2.3.4",
+// "nochg 1.2.3.4", "badauth", ...)
+func parseResponse(hostname string, statusCode int, body string) (*Result, error) {
+	var line string
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	if scanner.Scan() {
+		line = strings.TrimSpace(scanner.Text())
+	}
+	code, ip, _ := strings.Cut(line, " ")
+
+	switch code {
+	case "good", "nochg":
+		return &Result{Code: code, IP: strings.TrimSpace(ip)}, nil
+	}
+
+	respErr := &ResponseError{Hostname: hostname, StatusCode: statusCode}
+	if _, ok := responseCodes[code]; ok {
+		respErr.Code = code
+	} else if statusCode == http.StatusUnauthorized {
+		respErr.Code = "badauth"
+	} else {
+		respErr.Body = httputil.Truncate(body, 256)
+	}
+	return nil, respErr
+}
@@ -0,0 +1,103 @@
+package dyndns2
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       *Result
+		wantCode   string  // código de ResponseError, "" si no hay error o es desconocido
+		wantIs     []error // categorías que debe cumplir el error
+		wantNotIs  []error // categorías que no debe cumplir el error
+	}{
+		{name: "good", statusCode: http.StatusOK, body: "good 203.0.113.7", want: &Result{Code: "good", IP: "203.0.113.7"}},
+		{name: "nochg", statusCode: http.StatusOK, body: "nochg 203.0.113.7\n", want: &Result{Code: "nochg", IP: "203.0.113.7"}},
+		{name: "good sin IP", statusCode: http.StatusOK, body: "good", want: &Result{Code: "good"}},
+		{name: "solo la primera línea", statusCode: http.StatusOK, body: "good 2001:db8::1\nnochg 2001:db8::2", want: &Result{Code: "good", IP: "2001:db8::1"}},
+		{name: "espacios y CRLF", statusCode: http.StatusOK, body: "  nochg 203.0.113.7 \r\n", want: &Result{Code: "nochg", IP: "203.0.113.7"}},
+		{
+			name: "badauth", statusCode: http.StatusOK, body: "badauth",
+			wantCode: "badauth", wantIs: []error{ErrBadAuth, provider.ErrAuth}, wantNotIs: []error{provider.ErrUnavailable},
+		},
+		{
+			name: "401 sin código", statusCode: http.StatusUnauthorized, body: "Unauthorized",
+			wantCode: "badauth", wantIs: []error{ErrBadAuth, provider.ErrAuth},
+		},
+		{
+			name: "nohost", statusCode: http.StatusOK, body: "nohost",
+			wantCode: "nohost", wantIs: []error{ErrNoHost, provider.ErrValidation}, wantNotIs: []error{provider.ErrAuth},
+		},
+		{
+			name: "!donator", statusCode: http.StatusOK, body: "!donator",
+			wantCode: "!donator", wantIs: []error{ErrNotDonator, provider.ErrAuth},
+		},
+		{
+			name: "911", statusCode: http.StatusOK, body: "911",
+			wantCode: "911", wantIs: []error{ErrServerError, provider.ErrUnavailable}, wantNotIs: []error{provider.ErrValidation},
+		},
+		{
+			name: "dnserr", statusCode: http.StatusOK, body: "dnserr",
+			wantCode: "dnserr", wantIs: []error{ErrDNSError, provider.ErrUnavailable},
+		},
+		{
+			name: "respuesta desconocida", statusCode: http.StatusOK, body: "<html>mantenimiento</html>",
+			wantNotIs: []error{provider.ErrAuth, provider.ErrValidation, provider.ErrUnavailable},
+		},
+		{
+			name: "5xx desconocido", statusCode: http.StatusBadGateway, body: "Bad Gateway",
+			wantIs: []error{provider.ErrUnavailable}, wantNotIs: []error{provider.ErrAuth},
+		},
+		{
+			name: "cuerpo vacío", statusCode: http.StatusOK, body: "",
+			wantNotIs: []error{provider.ErrAuth, provider.ErrUnavailable},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseResponse("home.example.com", tt.statusCode, tt.body)
+
+			if tt.want != nil {
+				if err != nil {
+					t.Fatalf("error inesperado: %v", err)
+				}
+				if *got != *tt.want {
+					t.Errorf("resultado = %+v, want %+v", *got, *tt.want)
+				}
+				return
+			}
+
+			var respErr *ResponseError
+			if !errors.As(err, &respErr) {
+				t.Fatalf("error = %v, want *ResponseError", err)
+			}
+			if respErr.Code != tt.wantCode {
+				t.Errorf("Code = %q, want %q", respErr.Code, tt.wantCode)
+			}
+			if respErr.Hostname != "home.example.com" || respErr.StatusCode != tt.statusCode {
+				t.Errorf("Hostname/StatusCode = %q/%d", respErr.Hostname, respErr.StatusCode)
+			}
+			if tt.wantCode == "" && respErr.Body != tt.body {
+				t.Errorf("Body = %q, want %q", respErr.Body, tt.body)
+			}
+			for _, target := range tt.wantIs {
+				if !errors.Is(err, target) {
+					t.Errorf("errors.Is(%v, %v) = false", err, target)
+				}
+			}
+			for _, target := range tt.wantNotIs {
+				if errors.Is(err, target) {
+					t.Errorf("errors.Is(%v, %v) = true", err, target)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package dyndns2
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// Códigos de respuesta de error del protocolo dyndns2. Se comprueban con errors.Is
+// sobre el error retornado por el cliente.
+var (
+	ErrBadAuth     = errors.New("badauth: usuario o contraseña inválidos")
+	ErrNotDonator  = errors.New("!donator: la opción requiere una cuenta de pago")
+	ErrNotFQDN     = errors.New("notfqdn: el hostname no es un FQDN válido")
+	ErrNoHost      = errors.New("nohost: el hostname no existe en la cuenta")
+	ErrNumHost     = errors.New("numhost: demasiados hostnames en la petición")
+	ErrAbuse       = errors.New("abuse: el hostname está bloqueado por abuso")
+	ErrBadAgent    = errors.New("badagent: User-Agent bloqueado")
+	ErrDNSError    = errors.New("dnserr: error de DNS en el servidor")
+	ErrServerError = errors.New("911: error del servidor, esperar antes de reintentar")
+)
+
+// responseCodes asocia cada código de respuesta con su error
+var responseCodes = map[string]error{
+	"badauth":  ErrBadAuth,
+	"!donator": ErrNotDonator,
+	"notfqdn":  ErrNotFQDN,
+	"nohost":   ErrNoHost,
+	"numhost":  ErrNumHost,
+	"abuse":    ErrAbuse,
+	"badagent": ErrBadAgent,
+	"dnserr":   ErrDNSError,
+	"911":      ErrServerError,
+}
+
+// ResponseError es una respuesta de error del servidor dyndns2
+type ResponseError struct {
+	Hostname   string
+	Code       string // código de respuesta (badauth, nohost, 911, ...)
+	StatusCode int
+	Body       string // respuesta cruda si el código es desconocido
+}
+
+func (e *ResponseError) Error() string {
+	if code, ok := responseCodes[e.Code]; ok {
+		return fmt.Sprintf("error de dyndns2 para %s: %v", e.Hostname, code)
+	}
+	return fmt.Sprintf("error de dyndns2 para %s: respuesta inesperada (status %d): %q", e.Hostname, e.StatusCode, e.Body)
+}
+
+// Is permite usar errors.Is con el código concreto (ErrBadAuth, ErrNoHost, ...)
+// y con las categorías de internal/provider
+func (e *ResponseError) Is(target error) bool {
+	if code, ok := responseCodes[e.Code]; ok && code == target {
+		return true
+	}
+	switch target {
+	case provider.ErrAuth:
+		return e.Code == "badauth" || e.Code == "!donator" || e.Code == "abuse" || e.Code == "badagent"
+	case provider.ErrValidation:
+		return e.Code == "notfqdn" || e.Code == "nohost" || e.Code == "numhost"
+	case provider.ErrUnavailable:
+		return e.Code == "911" || e.Code == "dnserr" || (e.Code == "" && e.StatusCode >= 500)
+	}
+	return false
+}
+
+// blocksClient indica si la respuesta obliga a dejar de enviar actualizaciones hasta
+// que se corrija la configuración (el protocolo considera abuso reintentarlas)
+func (e *ResponseError) blocksClient() bool {
+	return e.Code == "badauth" || e.Code == "!donator" || e.Code == "abuse" || e.Code == "badagent"
+}
+
+// backoffRequired indica si la respuesta obliga a esperar antes del siguiente intento
+func (e *ResponseError) backoffRequired() bool {
+	return e.Code == "911" || e.Code == "dnserr"
+}
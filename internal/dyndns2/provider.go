@@ -0,0 +1,195 @@
+package dyndns2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// ProviderName es el nombre del proveedor en DNS_PROVIDER y ZONE_PROVIDERS
+const ProviderName = "dyndns2"
+
+// Espera exigida por el protocolo tras una respuesta 911 o dnserr
+const serverErrorBackoff = 30 * time.Minute
+
+var (
+	_ provider.DNSProvider = (*Provider)(nil)
+	_ provider.CycleAware  = (*Provider)(nil)
+)
+
+// Provider adapta el protocolo dyndns2 a provider.DNSProvider. El protocolo solo
+// permite asignar la IP de un hostname existente, así que:
+//   - cada hostname es su propia zona;
+//   - el estado inicial se obtiene con una consulta DNS y luego se recuerda la
+//     última IP enviada, para no reenviar IPs sin cambios (el servicio lo
+//     considera abuso);
+//   - tras badauth, !donator, abuse o badagent no se envían más actualizaciones
+//     hasta reiniciar, y tras 911 o dnserr se espera 30 minutos.
+type Provider struct {
+	client   *Client
+	resolver *net.Resolver
+
+	mu           sync.Mutex
+	lastIP       map[string]string // nombre|tipo -> última IP conocida en el servicio
+	blocked      error             // error que bloqueó el cliente hasta reiniciar
+	blockedUntil time.Time         // espera por 911 o dnserr
+}
+
+// NewProvider crea el proveedor dyndns2
+func NewProvider(client *Client) *Provider {
+	return &Provider{
+		client:   client,
+		resolver: net.DefaultResolver,
+		lastIP:   make(map[string]string),
+	}
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// Capabilities: el protocolo solo actualiza la IP
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{}
+}
+
+// ResolveZone: cada hostname es su propia zona
+func (p *Provider) ResolveZone(ctx context.Context, recordName string) (*provider.Zone, error) {
+	name := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(recordName)), ".")
+	return &provider.Zone{ID: name, Name: name}, nil
+}
+
+// ListRecords retorna el registro del filtro (el protocolo no permite listar)
+func (p *Provider) ListRecords(ctx context.Context, zone provider.Zone, filter provider.ListFilter) ([]provider.Record, error) {
+	if filter.Name == "" || filter.Type == "" {
+		return nil, fmt.Errorf("dyndns2 requiere nombre y tipo para listar registros: %w", provider.ErrValidation)
+	}
+	record, err := p.GetRecord(ctx, zone, filter.Name, filter.Type)
+	if err != nil {
+		return nil, err
+	}
+	return []provider.Record{*record}, nil
+}
+
+// GetRecord retorna la última IP conocida del hostname. La primera vez se consulta
+// por DNS; si no resuelve se retorna sin contenido para que se envíe la IP actual.
+func (p *Provider) GetRecord(ctx context.Context, zone provider.Zone, name, recordType string) (*provider.Record, error) {
+	key := stateKey(name, recordType)
+
+	p.mu.Lock()
+	ip, known := p.lastIP[key]
+	p.mu.Unlock()
+
+	if !known {
+		ip = p.lookup(ctx, name, recordType)
+		p.mu.Lock()
+		p.lastIP[key] = ip
+		p.mu.Unlock()
+	}
+
+	return &provider.Record{Type: recordType, Name: name, Content: ip, TTL: 1}, nil
+}
+
+// CreateRecord asigna la IP al hostname; dyndns2 no crea hostnames, deben existir en la cuenta
+func (p *Provider) CreateRecord(ctx context.Context, zone provider.Zone, record provider.Record) (*provider.Record, error) {
+	return p.update(ctx, record)
+}
+
+// UpdateRecord asigna la nueva IP al hostname
+func (p *Provider) UpdateRecord(ctx context.Context, zone provider.Zone, current provider.Record, update provider.RecordUpdate) (*provider.Record, error) {
+	return p.update(ctx, provider.ApplyUpdate(current, update))
+}
+
+// DeleteRecord no está soportado por el protocolo
+func (p *Provider) DeleteRecord(ctx context.Context, zone provider.Zone, record provider.Record) error {
+	return fmt.Errorf("dyndns2 no permite borrar hostnames: %w", provider.ErrValidation)
+}
+
+// BeginCycle falla mientras el cliente esté bloqueado o esperando tras 911/dnserr
+func (p *Provider) BeginCycle() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.checkBlocked()
+}
+
+func (p *Provider) EndCycle() error {
+	return nil
+}
+
+// update envía la IP del registro y recuerda la IP resultante
+func (p *Provider) update(ctx context.Context, record provider.Record) (*provider.Record, error) {
+	if record.Content == "" {
+		return nil, fmt.Errorf("dyndns2 requiere una IP para %s: %w", record.Name, provider.ErrValidation)
+	}
+
+	p.mu.Lock()
+	if err := p.checkBlocked(); err != nil {
+		p.mu.Unlock()
+		return nil, err
+	}
+	// No reenviar una IP que el servicio ya tiene
+	if p.lastIP[stateKey(record.Name, record.Type)] == record.Content {
+		p.mu.Unlock()
+		return &record, nil
+	}
+	p.mu.Unlock()
+
+	result, err := p.client.Update(ctx, record.Name, record.Content)
+	if err != nil {
+		var respErr *ResponseError
+		if errors.As(err, &respErr) {
+			p.mu.Lock()
+			if respErr.blocksClient() {
+				p.blocked = err
+			} else if respErr.backoffRequired() {
+				p.blockedUntil = time.Now().Add(serverErrorBackoff)
+			}
+			p.mu.Unlock()
+		}
+		return nil, err
+	}
+
+	if result.IP != "" {
+		record.Content = result.IP
+	}
+	p.mu.Lock()
+	p.lastIP[stateKey(record.Name, record.Type)] = record.Content
+	p.mu.Unlock()
+
+	return &record, nil
+}
+
+// checkBlocked retorna un error si no se deben enviar actualizaciones (requiere mu)
+func (p *Provider) checkBlocked() error {
+	if p.blocked != nil {
+		return fmt.Errorf("actualizaciones dyndns2 bloqueadas hasta reiniciar: %w", p.blocked)
+	}
+	if wait := time.Until(p.blockedUntil); wait > 0 {
+		return fmt.Errorf("el servidor dyndns2 pidió esperar, faltan %v: %w", wait.Round(time.Second), provider.ErrUnavailable)
+	}
+	return nil
+}
+
+// lookup obtiene la IP publicada del hostname por DNS ("" si no resuelve)
+func (p *Provider) lookup(ctx context.Context, name, recordType string) string {
+	network := "ip4"
+	if recordType == "AAAA" {
+		network = "ip6"
+	}
+	ips, err := p.resolver.LookupIP(ctx, network, name)
+	if err != nil || len(ips) == 0 {
+		return ""
+	}
+	return ips[0].String()
+}
+
+// stateKey identifica un hostname y tipo en el estado del proveedor
+func stateKey(name, recordType string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".") + "|" + recordType
+}
@@ -0,0 +1,158 @@
+// Package hetzner implementa un cliente de la API de Hetzner DNS (/api/v1/zones, /api/v1/records).
+package hetzner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/osmargm1202/orgmdns/internal/httputil"
+)
+
+// Tamaño de página usado al listar zonas y registros
+const listPerPage = 100
+
+type Client struct {
+	baseURL string
+	api     httputil.JSONClient
+}
+
+// Zone es una zona de Hetzner DNS
+type Zone struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	TTL  int    `json:"ttl"`
+}
+
+// Record es un registro DNS. Name es relativo a la zona ("@" para el apex).
+type Record struct {
+	ID     string `json:"id"`
+	ZoneID string `json:"zone_id"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	TTL    int    `json:"ttl"`
+}
+
+type ZonesResponse struct {
+	Zones []Zone `json:"zones"`
+	Meta  Meta   `json:"meta"`
+}
+
+type RecordsResponse struct {
+	Records []Record `json:"records"`
+	Meta    Meta     `json:"meta"`
+}
+
+type RecordResponse struct {
+	Record Record `json:"record"`
+}
+
+// Meta contiene la información de paginación de las respuestas de listado
+type Meta struct {
+	Pagination struct {
+		Page         int `json:"page"`
+		PerPage      int `json:"per_page"`
+		LastPage     int `json:"last_page"`
+		TotalEntries int `json:"total_entries"`
+	} `json:"pagination"`
+}
+
+// RecordRequest es el cuerpo para crear o actualizar un registro
+type RecordRequest struct {
+	ZoneID string `json:"zone_id"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	TTL    int    `json:"ttl,omitempty"`
+}
+
+// NewClient crea un cliente con token de API. baseURL vacío usa la API pública.
+func NewClient(token, baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = "https://dns.hetzner.com/api/v1"
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		api: httputil.JSONClient{
+			HTTPClient: &http.Client{},
+			Auth:       func(req *http.Request) { req.Header.Set("Auth-API-Token", token) },
+			NewError:   newAPIError,
+		},
+	}
+}
+
+// BaseURL retorna la URL base de la API usada por el cliente
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// ListZones obtiene las zonas con el nombre exacto indicado (GET /zones?name=)
+func (c *Client) ListZones(ctx context.Context, name string) ([]Zone, error) {
+	query := url.Values{}
+	query.Set("name", name)
+	reqURL := fmt.Sprintf("%s/zones?%s", c.baseURL, query.Encode())
+
+	var zonesResp ZonesResponse
+	if err := c.api.Do(ctx, "GET", reqURL, nil, &zonesResp); err != nil {
+		return nil, err
+	}
+	return zonesResp.Zones, nil
+}
+
+// ListRecords obtiene todos los registros de la zona recorriendo todas las páginas
+func (c *Client) ListRecords(ctx context.Context, zoneID string) ([]Record, error) {
+	var records []Record
+
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("zone_id", zoneID)
+		query.Set("page", strconv.Itoa(page))
+		query.Set("per_page", strconv.Itoa(listPerPage))
+		reqURL := fmt.Sprintf("%s/records?%s", c.baseURL, query.Encode())
+
+		var recordsResp RecordsResponse
+		if err := c.api.Do(ctx, "GET", reqURL, nil, &recordsResp); err != nil {
+			return nil, err
+		}
+		records = append(records, recordsResp.Records...)
+
+		// Parar cuando no hay más páginas (o la API no informa paginación)
+		if recordsResp.Meta.Pagination.LastPage <= page || len(recordsResp.Records) == 0 {
+			break
+		}
+	}
+
+	return records, nil
+}
+
+// CreateRecord crea un registro (POST /records)
+func (c *Client) CreateRecord(ctx context.Context, createReq RecordRequest) (*Record, error) {
+	reqURL := fmt.Sprintf("%s/records", c.baseURL)
+
+	var recordResp RecordResponse
+	if err := c.api.Do(ctx, "POST", reqURL, createReq, &recordResp); err != nil {
+		return nil, err
+	}
+	return &recordResp.Record, nil
+}
+
+// UpdateRecord reemplaza un registro (PUT /records/{id})
+func (c *Client) UpdateRecord(ctx context.Context, recordID string, updateReq RecordRequest) (*Record, error) {
+	reqURL := fmt.Sprintf("%s/records/%s", c.baseURL, url.PathEscape(recordID))
+
+	var recordResp RecordResponse
+	if err := c.api.Do(ctx, "PUT", reqURL, updateReq, &recordResp); err != nil {
+		return nil, err
+	}
+	return &recordResp.Record, nil
+}
+
+// DeleteRecord borra un registro (DELETE /records/{id})
+func (c *Client) DeleteRecord(ctx context.Context, recordID string) error {
+	reqURL := fmt.Sprintf("%s/records/%s", c.baseURL, url.PathEscape(recordID))
+	return c.api.Do(ctx, "DELETE", reqURL, nil, nil)
+}
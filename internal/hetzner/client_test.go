@@ -0,0 +1,195 @@
+package hetzner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// newTestProvider levanta la API de prueba con handler y crea el proveedor
+func newTestProvider(t *testing.T, handler http.HandlerFunc) *Provider {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Auth-API-Token"); got != "hetzner-token" {
+			t.Errorf("Auth-API-Token = %q", got)
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return NewProvider(NewClient("hetzner-token", server.URL+"/api/v1"))
+}
+
+func TestListRecordsPagination(t *testing.T) {
+	var pages []string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/zones":
+			if got := r.URL.Query().Get("name"); got != "example.com" {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"error":{"message":"zone not found","code":404}}`)
+				return
+			}
+			fmt.Fprint(w, `{"zones":[{"id":"zone-1","name":"example.com","ttl":86400}]}`)
+		case "/api/v1/records":
+			query := r.URL.Query()
+			if query.Get("zone_id") != "zone-1" || query.Get("per_page") != "100" {
+				t.Errorf("query = %s", r.URL.RawQuery)
+			}
+			pages = append(pages, query.Get("page"))
+			switch query.Get("page") {
+			case "1":
+				fmt.Fprint(w, `{"records":[
+					{"id":"r1","zone_id":"zone-1","type":"A","name":"home","value":"203.0.113.1","ttl":300},
+					{"id":"r2","zone_id":"zone-1","type":"AAAA","name":"home","value":"2001:db8::1"}
+				],"meta":{"pagination":{"page":1,"per_page":100,"last_page":2,"total_entries":3}}}`)
+			case "2":
+				fmt.Fprint(w, `{"records":[
+					{"id":"r3","zone_id":"zone-1","type":"A","name":"@","value":"203.0.113.2"}
+				],"meta":{"pagination":{"page":2,"per_page":100,"last_page":2,"total_entries":3}}}`)
+			}
+		default:
+			t.Errorf("petición inesperada: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	zone, err := p.ResolveZone(context.Background(), "home.example.com")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if zone.ID != "zone-1" || zone.Name != "example.com" {
+		t.Fatalf("zona = %+v", *zone)
+	}
+
+	records, err := p.ListRecords(context.Background(), *zone, provider.ListFilter{Type: "A"})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if fmt.Sprint(pages) != "[1 2]" {
+		t.Errorf("páginas = %v, want [1 2]", pages)
+	}
+	// El registro del apex no tiene ttl propio: hereda el de la zona (0)
+	want := []provider.Record{
+		{ID: "r1", Type: "A", Name: "home.example.com", Content: "203.0.113.1", TTL: 300},
+		{ID: "r3", Type: "A", Name: "example.com", Content: "203.0.113.2", TTL: 0},
+	}
+	if fmt.Sprint(records) != fmt.Sprint(want) {
+		t.Errorf("registros = %+v, want %+v", records, want)
+	}
+}
+
+func TestCreateAndUpdateRecord(t *testing.T) {
+	type call struct {
+		method, path, body string
+	}
+	var calls []call
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		calls = append(calls, call{r.Method, r.URL.Path, strings.TrimSpace(string(raw))})
+
+		var body RecordRequest
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("cuerpo inválido: %v", err)
+		}
+		json.NewEncoder(w).Encode(RecordResponse{Record: Record{
+			ID: "r1", ZoneID: body.ZoneID, Type: body.Type, Name: body.Name, Value: body.Value, TTL: body.TTL,
+		}})
+	})
+	zone := provider.Zone{ID: "zone-1", Name: "example.com"}
+
+	created, err := p.CreateRecord(context.Background(), zone, provider.Record{Type: "A", Name: "home.example.com", Content: "203.0.113.1", TTL: 1})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if created.ID != "r1" || created.Name != "home.example.com" || created.TTL != 0 {
+		t.Errorf("registro creado = %+v", *created)
+	}
+
+	// Cambiar la IP de un registro que hereda el TTL de la zona no lo fija
+	if _, err := p.UpdateRecord(context.Background(), zone, *created, provider.RecordUpdate{Content: "203.0.113.9"}); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if _, err := p.UpdateRecord(context.Background(), zone, *created, provider.RecordUpdate{TTL: 120}); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	want := []call{
+		{http.MethodPost, "/api/v1/records", `{"zone_id":"zone-1","type":"A","name":"home","value":"203.0.113.1"}`},
+		{http.MethodPut, "/api/v1/records/r1", `{"zone_id":"zone-1","type":"A","name":"home","value":"203.0.113.9"}`},
+		{http.MethodPut, "/api/v1/records/r1", `{"zone_id":"zone-1","type":"A","name":"home","value":"203.0.113.1","ttl":120}`},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("peticiones = %+v, want %+v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("petición %d = %+v, want %+v", i, calls[i], want[i])
+		}
+	}
+}
+
+func TestAPIErrorMapping(t *testing.T) {
+	tests := []struct {
+		statusCode  int
+		body        string
+		wantIs      error
+		wantMessage string
+	}{
+		{statusCode: http.StatusUnauthorized, body: `{"message":"Invalid authentication credentials"}`, wantIs: provider.ErrAuth, wantMessage: "Invalid authentication credentials"},
+		{statusCode: http.StatusForbidden, body: `{"error":{"message":"forbidden","code":403}}`, wantIs: provider.ErrAuth, wantMessage: "forbidden"},
+		{statusCode: http.StatusNotFound, body: `{"error":{"message":"record not found","code":404}}`, wantIs: provider.ErrNotFound, wantMessage: "record not found"},
+		{statusCode: http.StatusUnprocessableEntity, body: `{"error":{"message":"invalid value","code":422}}`, wantIs: provider.ErrValidation, wantMessage: "invalid value"},
+		{statusCode: http.StatusTooManyRequests, body: `{"error":{"message":"rate limit exceeded","code":429}}`, wantIs: provider.ErrRateLimited, wantMessage: "rate limit exceeded"},
+		{statusCode: http.StatusServiceUnavailable, body: "upstream connect error", wantIs: provider.ErrUnavailable, wantMessage: "upstream connect error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				fmt.Fprint(w, tt.body)
+			})
+
+			_, err := p.client.UpdateRecord(context.Background(), "r1", RecordRequest{ZoneID: "zone-1", Type: "A", Name: "home", Value: "203.0.113.1"})
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want *APIError", err)
+			}
+			if apiErr.Message != tt.wantMessage || apiErr.Method != http.MethodPut || apiErr.Path != "/api/v1/records/r1" {
+				t.Errorf("APIError = %+v", *apiErr)
+			}
+			if !errors.Is(err, tt.wantIs) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.wantIs)
+			}
+			for _, other := range []error{provider.ErrAuth, provider.ErrNotFound, provider.ErrValidation, provider.ErrRateLimited, provider.ErrUnavailable} {
+				if other != tt.wantIs && errors.Is(err, other) {
+					t.Errorf("errors.Is(%v, %v) = true", err, other)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveZoneNotFound(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		// La API responde lista vacía o 404 según la versión
+		if r.URL.Query().Get("name") == "example.net" {
+			fmt.Fprint(w, `{"zones":[]}`)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"message":"zone not found","code":404}}`)
+	})
+
+	_, err := p.ResolveZone(context.Background(), "home.example.net")
+	if !errors.Is(err, provider.ErrNotFound) {
+		t.Errorf("error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,52 @@
+package hetzner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/osmargm1202/orgmdns/internal/httputil"
+)
+
+// APIError es un error retornado por la API de Hetzner DNS
+type APIError struct {
+	StatusCode int
+	Message    string
+	Method     string
+	Path       string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("error de API de Hetzner DNS: %s %s status %d", e.Method, e.Path, e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Is clasifica el status HTTP en las categorías de internal/provider
+func (e *APIError) Is(target error) bool {
+	return httputil.StatusIs(e.StatusCode, target)
+}
+
+// newAPIError construye un APIError a partir de la respuesta HTTP
+func newAPIError(resp *http.Response, body []byte) error {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	apiErr.Method, apiErr.Path = httputil.RequestPath(resp)
+
+	// La API responde {"error": {"message": ..., "code": ...}} o {"message": ...}
+	var errResp struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Message != "" {
+		apiErr.Message = errResp.Error.Message
+	} else if err == nil && errResp.Message != "" {
+		apiErr.Message = errResp.Message
+	} else {
+		apiErr.Message = httputil.Truncate(string(body), 512)
+	}
+	return apiErr
+}
@@ -0,0 +1,182 @@
+package hetzner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// ProviderName es el nombre del proveedor en DNS_PROVIDER y ZONE_PROVIDERS
+const ProviderName = "hetzner"
+
+var _ provider.DNSProvider = (*Provider)(nil)
+
+// Provider adapta la API de Hetzner DNS a provider.DNSProvider
+type Provider struct {
+	client *Client
+
+	mu    sync.Mutex
+	zones map[string]string // nombre de zona -> ID (descubiertas)
+}
+
+// NewProvider crea el proveedor de Hetzner DNS. Como la API usa IDs, todas las
+// zonas (también las de ZONE_PROVIDERS) se resuelven con GET /zones?name=.
+func NewProvider(client *Client) *Provider {
+	return &Provider{
+		client: client,
+		zones:  make(map[string]string),
+	}
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// Capabilities: solo TTL. El TTL automático es el de la zona (registro sin ttl).
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{TTL: true, ListZone: true}
+}
+
+// ResolveZone busca la zona por sufijo más largo entre las conocidas y, si no hay,
+// la descubre con la API
+func (p *Provider) ResolveZone(ctx context.Context, recordName string) (*provider.Zone, error) {
+	labels := strings.Split(normalizeName(recordName), ".")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := 0; i < len(labels)-1; i++ {
+		candidate := strings.Join(labels[i:], ".")
+		if id, ok := p.zones[candidate]; ok {
+			return &provider.Zone{ID: id, Name: candidate}, nil
+		}
+	}
+
+	for i := 0; i < len(labels)-1; i++ {
+		candidate := strings.Join(labels[i:], ".")
+		zones, err := p.client.ListZones(ctx, candidate)
+		if provider.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error buscando zona %s: %w", candidate, err)
+		}
+		for _, zone := range zones {
+			if normalizeName(zone.Name) == candidate {
+				p.zones[candidate] = zone.ID
+				return &provider.Zone{ID: zone.ID, Name: candidate}, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no se encontró zona de Hetzner DNS para %s: %w", recordName, provider.ErrNotFound)
+}
+
+// ListRecords obtiene todos los registros de la zona y filtra localmente
+// (la API no filtra por nombre ni tipo)
+func (p *Provider) ListRecords(ctx context.Context, zone provider.Zone, filter provider.ListFilter) ([]provider.Record, error) {
+	records, err := p.client.ListRecords(ctx, zone.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []provider.Record
+	for _, record := range records {
+		converted := p.toProviderRecord(zone, record)
+		if filter.Name != "" && converted.Name != normalizeName(filter.Name) {
+			continue
+		}
+		if filter.Type != "" && converted.Type != filter.Type {
+			continue
+		}
+		result = append(result, converted)
+	}
+	return result, nil
+}
+
+func (p *Provider) GetRecord(ctx context.Context, zone provider.Zone, name, recordType string) (*provider.Record, error) {
+	records, err := p.ListRecords(ctx, zone, provider.ListFilter{Name: name, Type: recordType})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("registro %s %s: %w", recordType, name, provider.ErrNotFound)
+	}
+	return &records[0], nil
+}
+
+func (p *Provider) CreateRecord(ctx context.Context, zone provider.Zone, record provider.Record) (*provider.Record, error) {
+	created, err := p.client.CreateRecord(ctx, p.recordRequest(zone, record))
+	if err != nil {
+		return nil, err
+	}
+	result := p.toProviderRecord(zone, *created)
+	return &result, nil
+}
+
+func (p *Provider) UpdateRecord(ctx context.Context, zone provider.Zone, current provider.Record, update provider.RecordUpdate) (*provider.Record, error) {
+	updated, err := p.client.UpdateRecord(ctx, current.ID, p.recordRequest(zone, provider.ApplyUpdate(current, update)))
+	if err != nil {
+		return nil, err
+	}
+	result := p.toProviderRecord(zone, *updated)
+	return &result, nil
+}
+
+func (p *Provider) DeleteRecord(ctx context.Context, zone provider.Zone, record provider.Record) error {
+	return p.client.DeleteRecord(ctx, record.ID)
+}
+
+// recordRequest construye el cuerpo de creación o actualización con el nombre
+// relativo. Con TTL automático (1) o heredado (0) se omite ttl y el registro usa
+// el TTL de la zona.
+func (p *Provider) recordRequest(zone provider.Zone, record provider.Record) RecordRequest {
+	ttl := record.TTL
+	if ttl <= 1 {
+		ttl = 0
+	}
+	return RecordRequest{
+		ZoneID: zone.ID,
+		Type:   record.Type,
+		Name:   relativeName(record.Name, zone.Name),
+		Value:  record.Content,
+		TTL:    ttl,
+	}
+}
+
+// toProviderRecord convierte un registro de la API (nombre relativo) al tipo común.
+// Los registros sin TTL propio (0) heredan el TTL de la zona.
+func (p *Provider) toProviderRecord(zone provider.Zone, record Record) provider.Record {
+	return provider.Record{
+		ID:      record.ID,
+		Type:    record.Type,
+		Name:    absoluteName(record.Name, zone.Name),
+		Content: record.Value,
+		TTL:     record.TTL,
+	}
+}
+
+// relativeName convierte un FQDN al nombre relativo a la zona ("@" para el apex)
+func relativeName(name, zone string) string {
+	name = normalizeName(name)
+	if name == zone {
+		return "@"
+	}
+	return strings.TrimSuffix(name, "."+zone)
+}
+
+// absoluteName convierte un nombre relativo a la zona en FQDN
+func absoluteName(name, zone string) string {
+	if name == "@" || name == "" {
+		return zone
+	}
+	return normalizeName(name) + "." + zone
+}
+
+// normalizeName pasa un nombre DNS a minúsculas y sin punto final
+func normalizeName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
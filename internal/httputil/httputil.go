@@ -0,0 +1,130 @@
+// Package httputil contiene el transporte común de los clientes HTTP de los
+// proveedores DNS: timeout por defecto, lectura de la respuesta, decodificación
+// JSON y clasificación del status en las categorías de internal/provider.
+package httputil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// RequestTimeout es el tiempo máximo de cada petición si el contexto no trae deadline
+const RequestTimeout = 30 * time.Second
+
+// Tamaño máximo de la respuesta que se lee
+const maxResponseSize = 8 << 20
+
+// Do envía la petición con RequestTimeout si su contexto no trae deadline y
+// retorna la respuesta con el cuerpo ya leído y cerrado
+func Do(client *http.Client, req *http.Request) (*http.Response, []byte, error) {
+	if _, ok := req.Context().Deadline(); !ok {
+		ctx, cancel := context.WithTimeout(req.Context(), RequestTimeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error haciendo request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error leyendo respuesta: %w", err)
+	}
+	return resp, body, nil
+}
+
+// JSONClient hace peticiones a una API JSON: agrega la autenticación, decodifica
+// las respuestas 2xx y convierte las demás en el error de la API con NewError
+type JSONClient struct {
+	HTTPClient *http.Client
+	Auth       func(req *http.Request)
+	NewError   func(resp *http.Response, body []byte) error
+}
+
+// Do ejecuta una petición y decodifica la respuesta en out (nil = se descarta)
+func (c *JSONClient) Do(ctx context.Context, method, url string, payload interface{}, out interface{}) error {
+	var reqBody io.Reader
+	if payload != nil {
+		jsonData, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("error serializando request: %w", err)
+		}
+		reqBody = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return fmt.Errorf("error creando request: %w", err)
+	}
+	if c.Auth != nil {
+		c.Auth(req)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, body, err := Do(httpClient, req)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return c.NewError(resp, body)
+	}
+
+	if out == nil || len(body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error parseando respuesta: %w", err)
+	}
+	return nil
+}
+
+// StatusIs clasifica un status HTTP en las categorías de internal/provider, para
+// el método Is de los errores de API
+func StatusIs(statusCode int, target error) bool {
+	switch target {
+	case provider.ErrAuth:
+		return statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden
+	case provider.ErrNotFound:
+		return statusCode == http.StatusNotFound
+	case provider.ErrRateLimited:
+		return statusCode == http.StatusTooManyRequests
+	case provider.ErrValidation:
+		return statusCode == http.StatusBadRequest || statusCode == http.StatusUnprocessableEntity
+	case provider.ErrUnavailable:
+		return statusCode >= 500
+	}
+	return false
+}
+
+// RequestPath retorna el método y la ruta de la petición de la respuesta, para
+// los mensajes de error
+func RequestPath(resp *http.Response) (method, path string) {
+	if resp.Request == nil {
+		return "", ""
+	}
+	return resp.Request.Method, resp.Request.URL.Path
+}
+
+// Truncate recorta s a max bytes
+func Truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "..."
+}
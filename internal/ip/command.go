@@ -0,0 +1,71 @@
+package ip
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Espera máxima a la salida del comando tras cancelarlo
+const commandWaitDelay = 500 * time.Millisecond
+
+// CommandSource obtiene la IP pública ejecutando un comando de shell que la
+// imprime en la primera línea de stdout (por ejemplo, una consulta al router).
+// El comando recibe ORGMDNS_RECORD_TYPE (A o AAAA) y ORGMDNS_IP_FAMILY (4 o 6).
+type CommandSource struct {
+	command string
+}
+
+// NewCommandSource crea una fuente que ejecuta command con sh -c
+func NewCommandSource(command string) *CommandSource {
+	return &CommandSource{command: command}
+}
+
+func (s *CommandSource) Name() string {
+	return "cmd:" + s.command
+}
+
+// PublicIP ejecuta el comando (se mata al cancelarse ctx) y lee la IP de su salida
+func (s *CommandSource) PublicIP(ctx context.Context, recordType string) (string, error) {
+	network, err := networkFor(recordType, "ip")
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.command)
+	cmd.Env = append(os.Environ(),
+		"ORGMDNS_RECORD_TYPE="+recordType,
+		"ORGMDNS_IP_FAMILY="+strings.TrimPrefix(network, "ip"),
+	)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Los procesos hijos que sigan vivos tras matar sh no deben bloquear la espera
+	cmd.WaitDelay = commandWaitDelay
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("el comando no terminó a tiempo: %w", ctx.Err())
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return "", fmt.Errorf("el comando falló (%w): %s", err, truncate(strings.TrimSpace(stderr.String()), 256))
+		}
+		return "", fmt.Errorf("el comando falló: %w", err)
+	}
+
+	scanner := bufio.NewScanner(&stdout)
+	if scanner.Scan() {
+		return strings.TrimSpace(scanner.Text()), nil
+	}
+	return "", fmt.Errorf("el comando no imprimió ninguna IP")
+}
@@ -1,18 +1,25 @@
 package ip
 
 import (
+	"context"
 	"net/http"
-	"time"
 )
 
 // CheckInternetConnection verifica si hay conexión a internet
 // Similar a la función check_internet() de Python
-func CheckInternetConnection() bool {
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
+func CheckInternetConnection(ctx context.Context) bool {
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
 
 	// Intentar hacer una petición a Google (como en Python)
-	_, err := client.Get("https://www.google.com")
-	return err == nil
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://www.google.com", nil)
+	if err != nil {
+		return false
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return true
 }
@@ -0,0 +1,187 @@
+package ip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNoConsensus indica que las fuentes no alcanzaron el quorum sobre una misma IP
+var ErrNoConsensus = errors.New("las fuentes de IP no coinciden")
+
+// Vote es la respuesta de una fuente en una consulta por consenso
+type Vote struct {
+	Source string
+	IP     string // "" si la fuente falló
+	Err    error
+}
+
+// ConsensusResult es el resultado de una consulta por consenso
+type ConsensusResult struct {
+	RecordType string
+	IP         string // IP acordada, "" si no hubo consenso
+	Quorum     int
+	Votes      []Vote // en el orden de las fuentes
+}
+
+// Disagreement indica si alguna fuente respondió una IP distinta de la acordada
+// (o, sin consenso, si respondieron IPs distintas)
+func (r ConsensusResult) Disagreement() bool {
+	first := ""
+	for _, vote := range r.Votes {
+		if vote.Err != nil {
+			continue
+		}
+		if r.IP != "" && vote.IP != r.IP {
+			return true
+		}
+		if first != "" && vote.IP != first {
+			return true
+		}
+		first = vote.IP
+	}
+	return false
+}
+
+// Summary describe los votos agrupados por IP, por ejemplo
+// "203.0.113.7 (2: stun:..., dns:...); 198.51.100.1 (1: http:...); error (1: http:...)"
+func (r ConsensusResult) Summary() string {
+	bySource := make(map[string][]string)
+	var ips []string
+	var failed []string
+	for _, vote := range r.Votes {
+		if vote.Err != nil {
+			failed = append(failed, vote.Source)
+			continue
+		}
+		if _, ok := bySource[vote.IP]; !ok {
+			ips = append(ips, vote.IP)
+		}
+		bySource[vote.IP] = append(bySource[vote.IP], vote.Source)
+	}
+	sort.SliceStable(ips, func(i, j int) bool { return len(bySource[ips[i]]) > len(bySource[ips[j]]) })
+
+	var parts []string
+	for _, ip := range ips {
+		parts = append(parts, fmt.Sprintf("%s (%d: %s)", ip, len(bySource[ip]), strings.Join(bySource[ip], ", ")))
+	}
+	if len(failed) > 0 {
+		parts = append(parts, fmt.Sprintf("error (%d: %s)", len(failed), strings.Join(failed, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Consensus consulta todas sus fuentes en paralelo y solo acepta una IP si al menos
+// quorum fuentes coinciden en ella y ninguna otra IP tiene tantos votos. Así una
+// sola respuesta errónea (un servicio defectuoso, un STUN detrás de otro NAT) no
+// cambia los registros.
+type Consensus struct {
+	entries []ChainEntry
+	quorum  int
+	report  func(ConsensusResult)
+}
+
+var _ IPSource = (*Consensus)(nil)
+
+// NewConsensus crea una fuente por consenso. report (opcional) recibe cada resultado
+// en el que las fuentes no coinciden, haya o no consenso.
+func NewConsensus(quorum int, report func(ConsensusResult), entries ...ChainEntry) *Consensus {
+	if quorum < 1 {
+		quorum = 1
+	}
+	return &Consensus{entries: entries, quorum: quorum, report: report}
+}
+
+func (c *Consensus) Name() string {
+	names := make([]string, 0, len(c.entries))
+	for _, entry := range c.entries {
+		names = append(names, entry.Source.Name())
+	}
+	return fmt.Sprintf("consensus(quorum %d: %s)", c.quorum, strings.Join(names, ", "))
+}
+
+// PublicIP retorna la IP acordada o un error que envuelve ErrNoConsensus
+func (c *Consensus) PublicIP(ctx context.Context, recordType string) (string, error) {
+	result, err := c.Query(ctx, recordType)
+	if err != nil {
+		return "", err
+	}
+	return result.IP, nil
+}
+
+// Query consulta las fuentes de la familia en paralelo, cada una con su timeout,
+// y retorna el detalle de los votos
+func (c *Consensus) Query(ctx context.Context, recordType string) (ConsensusResult, error) {
+	result := ConsensusResult{RecordType: recordType, Quorum: c.quorum}
+	if _, err := networkFor(recordType, "ip"); err != nil {
+		return result, err
+	}
+
+	var entries []ChainEntry
+	for _, entry := range c.entries {
+		if entry.Handles(recordType) {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) < c.quorum {
+		return result, fmt.Errorf("%w: solo hay %d fuentes para %s y el quorum es %d", ErrNoConsensus, len(entries), familyName(recordType), c.quorum)
+	}
+
+	result.Votes = make([]Vote, len(entries))
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		wg.Add(1)
+		go func(i int, entry ChainEntry) {
+			defer wg.Done()
+			ip, err := querySource(ctx, entry, recordType)
+			result.Votes[i] = Vote{Source: entry.Source.Name(), IP: ip, Err: err}
+		}(i, entry)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return result, ctx.Err()
+	}
+
+	// Contar votos por IP; un empate en el máximo no es consenso
+	counts := make(map[string]int)
+	for _, vote := range result.Votes {
+		if vote.Err == nil {
+			counts[vote.IP]++
+		}
+	}
+	best, bestCount, tie := "", 0, false
+	for ip, count := range counts {
+		switch {
+		case count > bestCount:
+			best, bestCount, tie = ip, count, false
+		case count == bestCount:
+			tie = true
+		}
+	}
+	if bestCount >= c.quorum && !tie {
+		result.IP = best
+	}
+
+	if c.report != nil && result.Disagreement() {
+		c.report(result)
+	}
+
+	if result.IP == "" {
+		if len(counts) == 0 {
+			var errs []error
+			for _, vote := range result.Votes {
+				errs = append(errs, fmt.Errorf("%s: %w", vote.Source, vote.Err))
+			}
+			return result, fmt.Errorf("no se pudo obtener IP pública %s desde ninguna fuente: %w", familyName(recordType), errors.Join(errs...))
+		}
+		if tie && bestCount >= c.quorum {
+			return result, fmt.Errorf("%w: empate entre IPs %s con %d votos: %s", ErrNoConsensus, familyName(recordType), bestCount, result.Summary())
+		}
+		return result, fmt.Errorf("%w: ninguna IP %s alcanzó el quorum de %d: %s", ErrNoConsensus, familyName(recordType), c.quorum, result.Summary())
+	}
+	return result, nil
+}
@@ -0,0 +1,142 @@
+package ip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// Métodos de la fuente DNS: qué nombre se consulta y cómo se lee la respuesta
+const (
+	DNSMethodOpenDNS = "opendns" // myip.opendns.com A/AAAA: el resolver responde la dirección de quien consulta
+	DNSMethodGoogle  = "google"  // o-o.myaddr.l.google.com TXT: el servidor autoritativo responde la dirección de quien consulta
+)
+
+// Nombres consultados por cada método
+const (
+	openDNSMyIPName = "myip.opendns.com."
+	googleMyIPName  = "o-o.myaddr.l.google.com."
+)
+
+// Servidores por defecto de cada método. El TXT de Google se consulta a sus
+// servidores autoritativos: a través de un resolver recursivo respondería la
+// dirección del resolver.
+var (
+	DefaultOpenDNSResolvers = []string{"resolver1.opendns.com:53", "resolver2.opendns.com:53"}
+	DefaultGoogleResolvers  = []string{"ns1.google.com:53", "ns2.google.com:53"}
+)
+
+// DNSSource obtiene la IP pública preguntando a servidores DNS por la propia
+// dirección. Los servidores se prueban en orden hasta que uno responda.
+type DNSSource struct {
+	method    string
+	resolvers []string // host:puerto
+}
+
+// NewDNSSource crea una fuente DNS con el método indicado ("" = DNSMethodOpenDNS) y
+// sus servidores (host o host:puerto, puerto 53 por defecto; vacío = los del método).
+// Con servidores propios se puede apuntar a un resolver local de pruebas.
+func NewDNSSource(method string, resolvers ...string) *DNSSource {
+	if method == "" {
+		method = DNSMethodOpenDNS
+	}
+	if len(resolvers) == 0 {
+		switch method {
+		case DNSMethodOpenDNS:
+			resolvers = DefaultOpenDNSResolvers
+		case DNSMethodGoogle:
+			resolvers = DefaultGoogleResolvers
+		}
+	}
+
+	s := &DNSSource{method: method}
+	for _, resolver := range resolvers {
+		if _, _, err := net.SplitHostPort(resolver); err != nil {
+			resolver = net.JoinHostPort(resolver, "53")
+		}
+		s.resolvers = append(s.resolvers, resolver)
+	}
+	return s
+}
+
+func (s *DNSSource) Name() string {
+	return "dns:" + s.method + "@" + strings.Join(s.resolvers, "|")
+}
+
+// PublicIP consulta los servidores por udp4 o udp6 según el tipo de registro, para
+// que la dirección que ven (y responden) sea la de esa familia
+func (s *DNSSource) PublicIP(ctx context.Context, recordType string) (string, error) {
+	network, err := networkFor(recordType, "udp")
+	if err != nil {
+		return "", err
+	}
+
+	m := new(dns.Msg)
+	switch s.method {
+	case DNSMethodOpenDNS:
+		qtype := dns.TypeA
+		if recordType == RecordTypeAAAA {
+			qtype = dns.TypeAAAA
+		}
+		m.SetQuestion(openDNSMyIPName, qtype)
+	case DNSMethodGoogle:
+		m.SetQuestion(googleMyIPName, dns.TypeTXT)
+	default:
+		return "", fmt.Errorf("método DNS desconocido: %s (válidos: opendns, google)", s.method)
+	}
+	if len(s.resolvers) == 0 {
+		return "", fmt.Errorf("no hay servidores DNS configurados")
+	}
+
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	var errs []error
+	for _, resolver := range s.resolvers {
+		if ctx.Err() != nil {
+			break
+		}
+		ip, err := s.query(ctx, recordType, network, resolver, m)
+		if err == nil {
+			return ip, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", resolver, err))
+	}
+	return "", fmt.Errorf("error consultando %s: %w", strings.TrimSuffix(m.Question[0].Name, "."), errors.Join(errs...))
+}
+
+// query envía la consulta a un servidor y extrae de la respuesta la IP de la familia
+func (s *DNSSource) query(ctx context.Context, recordType, network, resolver string, m *dns.Msg) (string, error) {
+	client := &dns.Client{Net: network}
+	resp, _, err := client.ExchangeContext(ctx, m.Copy(), resolver)
+	if err != nil {
+		return "", err
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return "", fmt.Errorf("respuesta %s", dns.RcodeToString[resp.Rcode])
+	}
+
+	for _, rr := range resp.Answer {
+		var ip net.IP
+		switch rr := rr.(type) {
+		case *dns.A:
+			ip = rr.A
+		case *dns.AAAA:
+			ip = rr.AAAA
+		case *dns.TXT:
+			// Con EDNS Client Subnet Google agrega un TXT "edns0-client-subnet ...";
+			// la dirección propia es el TXT que es solo una IP
+			if len(rr.Txt) == 1 {
+				ip = net.ParseIP(rr.Txt[0])
+			}
+		}
+		if ip != nil && matchesNetwork(ip, network) {
+			return ip.String(), nil
+		}
+	}
+	return "", fmt.Errorf("la respuesta no contiene una dirección %s", familyName(recordType))
+}
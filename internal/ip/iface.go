@@ -0,0 +1,52 @@
+package ip
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// InterfaceSource toma la IP pública de una interfaz de red local, para equipos
+// con la dirección pública asignada directamente (sin NAT) o prefijos IPv6 globales
+type InterfaceSource struct {
+	iface string
+}
+
+// NewInterfaceSource crea una fuente que lee las direcciones de la interfaz indicada
+func NewInterfaceSource(iface string) *InterfaceSource {
+	return &InterfaceSource{iface: iface}
+}
+
+func (s *InterfaceSource) Name() string {
+	return "interface:" + s.iface
+}
+
+// PublicIP retorna la primera dirección pública de la familia (ver CheckPublic)
+func (s *InterfaceSource) PublicIP(ctx context.Context, recordType string) (string, error) {
+	network, err := networkFor(recordType, "ip")
+	if err != nil {
+		return "", err
+	}
+
+	iface, err := net.InterfaceByName(s.iface)
+	if err != nil {
+		return "", fmt.Errorf("error buscando la interfaz %s: %w", s.iface, err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", fmt.Errorf("error leyendo las direcciones de %s: %w", s.iface, err)
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		ip := ipNet.IP
+		if !matchesNetwork(ip, network) || CheckPublic(ip.String()) != nil {
+			continue
+		}
+		return ip.String(), nil
+	}
+	return "", fmt.Errorf("la interfaz %s no tiene una dirección %s pública", s.iface, familyName(recordType))
+}
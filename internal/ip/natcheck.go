@@ -0,0 +1,115 @@
+package ip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// NATStatus compara la IP pública detectada con la dirección WAN que informa el router
+type NATStatus struct {
+	RecordType string
+	PublicIP   string // IP detectada por la fuente principal
+	Router     string // fuente del router que respondió
+	RouterIP   string // dirección WAN del router, "" si no se pudo consultar
+	Err        error  // error de las fuentes del router
+}
+
+// DoubleNAT indica si el router respondió una dirección WAN distinta de la IP
+// pública: hay otro NAT (del proveedor o un segundo router) entre el router e internet
+func (s NATStatus) DoubleNAT() bool {
+	return s.Err == nil && s.RouterIP != s.PublicIP
+}
+
+// NATCheck envuelve una fuente de IP pública y, tras cada detección, consulta la
+// dirección WAN del router (UPnP IGD, NAT-PMP/PCP) para detectar doble NAT. La IP
+// que retorna es siempre la de la fuente envuelta.
+type NATCheck struct {
+	source  IPSource
+	routers []ChainEntry
+	report  func(NATStatus)
+
+	mu   sync.Mutex
+	last map[string]string // familia -> último estado reportado
+}
+
+var _ IPSource = (*NATCheck)(nil)
+
+// NewNATCheck crea la comprobación de doble NAT. report recibe el estado de cada
+// familia la primera vez y cada vez que cambia (no en cada ciclo).
+func NewNATCheck(source IPSource, report func(NATStatus), routers ...ChainEntry) *NATCheck {
+	return &NATCheck{source: source, routers: routers, report: report, last: make(map[string]string)}
+}
+
+// DefaultRouterEntries retorna las fuentes del router por defecto: UPnP IGD
+// descubierto con SSDP y NAT-PMP/PCP al gateway por defecto, solo para IPv4
+func DefaultRouterEntries(timeout time.Duration) []ChainEntry {
+	types := []string{RecordTypeA}
+	return []ChainEntry{
+		{Source: NewUPnPSource(""), Timeout: timeout, Types: types},
+		{Source: NewNATPMPSource(""), Timeout: timeout, Types: types},
+	}
+}
+
+func (c *NATCheck) Name() string {
+	return c.source.Name()
+}
+
+// PublicIP obtiene la IP de la fuente envuelta y la compara con la dirección WAN del
+// router. Un fallo del router no afecta al resultado.
+func (c *NATCheck) PublicIP(ctx context.Context, recordType string) (string, error) {
+	ip, err := c.source.PublicIP(ctx, recordType)
+	if err != nil {
+		return "", err
+	}
+
+	status := NATStatus{RecordType: recordType, PublicIP: ip}
+	var errs []error
+	handled := false
+	for _, entry := range c.routers {
+		if !entry.Handles(recordType) {
+			continue
+		}
+		handled = true
+		if ctx.Err() != nil {
+			return ip, nil
+		}
+		routerIP, err := queryAddress(ctx, entry, recordType)
+		if err == nil {
+			status.Router, status.RouterIP = entry.Source.Name(), routerIP
+			break
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", entry.Source.Name(), err))
+	}
+	if !handled {
+		return ip, nil
+	}
+	if status.RouterIP == "" {
+		status.Err = errors.Join(errs...)
+	}
+
+	c.notify(status)
+	return ip, nil
+}
+
+// notify llama a report si el estado de la familia cambió desde el último reporte
+func (c *NATCheck) notify(status NATStatus) {
+	if c.report == nil {
+		return
+	}
+	key := status.PublicIP + "|" + status.RouterIP
+	if status.Err != nil {
+		key += "|error"
+	}
+
+	c.mu.Lock()
+	changed := c.last[status.RecordType] != key
+	c.last[status.RecordType] = key
+	c.mu.Unlock()
+
+	if changed {
+		c.report(status)
+	}
+}
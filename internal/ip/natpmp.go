@@ -0,0 +1,272 @@
+package ip
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Puerto de NAT-PMP (RFC 6886) y PCP (RFC 6887) en el gateway
+const natpmpPort = "5351"
+
+// Versiones y códigos de NAT-PMP y PCP
+const (
+	natpmpVersion         = 0
+	pcpVersion            = 2
+	pcpOpMap              = 1
+	pcpResultUnsuppVer    = 1  // también lo usa NAT-PMP
+	pcpMapLifetime        = 30 // segundos del mapeo temporal de PCP
+	natpmpInitialInterval = 250 * time.Millisecond
+)
+
+// Ruta de la tabla de rutas IPv4 de Linux, para descubrir el gateway por defecto
+const procNetRoute = "/proc/net/route"
+
+// NATPMPSource obtiene la dirección WAN del router con NAT-PMP y, si el router solo
+// habla PCP, con una petición MAP de PCP (que crea un mapeo temporal y lo elimina).
+// Solo informa IPv4.
+type NATPMPSource struct {
+	gateway string // IP (o IP:puerto) del gateway, vacío = gateway por defecto
+}
+
+// NewNATPMPSource crea una fuente NAT-PMP/PCP (gateway vacío = el gateway por
+// defecto de la tabla de rutas, solo en Linux)
+func NewNATPMPSource(gateway string) *NATPMPSource {
+	return &NATPMPSource{gateway: gateway}
+}
+
+func (s *NATPMPSource) Name() string {
+	if s.gateway == "" {
+		return "natpmp"
+	}
+	return "natpmp:" + s.gateway
+}
+
+// PublicIP pregunta la dirección externa al gateway por UDP 5351
+func (s *NATPMPSource) PublicIP(ctx context.Context, recordType string) (string, error) {
+	if recordType != RecordTypeA {
+		return "", fmt.Errorf("NAT-PMP/PCP solo informa la dirección IPv4 externa")
+	}
+
+	gateway := s.gateway
+	if gateway == "" {
+		gw, err := defaultGateway()
+		if err != nil {
+			return "", err
+		}
+		gateway = gw.String()
+	}
+
+	if _, _, err := net.SplitHostPort(gateway); err != nil {
+		gateway = net.JoinHostPort(gateway, natpmpPort)
+	}
+
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp4", gateway)
+	if err != nil {
+		return "", fmt.Errorf("error conectando al gateway %s: %w", gateway, err)
+	}
+	defer conn.Close()
+
+	// Petición de dirección externa de NAT-PMP: versión 0, opcode 0
+	resp, err := udpExchange(ctx, conn, []byte{natpmpVersion, 0}, func(b []byte) bool {
+		return len(b) >= 4 && (b[0] == pcpVersion || b[1] == 128)
+	})
+	if err != nil {
+		return "", fmt.Errorf("NAT-PMP: %w", err)
+	}
+
+	ip, pcpOnly, err := parseNATPMPResponse(resp)
+	if pcpOnly {
+		return pcpExternalIP(ctx, conn)
+	}
+	return ip, err
+}
+
+// parseNATPMPResponse interpreta la respuesta de dirección externa de NAT-PMP
+// (RFC 6886, sección 3.2). pcpOnly indica que el gateway solo habla PCP: respondió
+// con versión 2 o con UNSUPP_VERSION.
+func parseNATPMPResponse(resp []byte) (ip string, pcpOnly bool, err error) {
+	if len(resp) < 4 {
+		return "", false, fmt.Errorf("NAT-PMP: respuesta demasiado corta")
+	}
+	result := binary.BigEndian.Uint16(resp[2:4])
+	if resp[0] == pcpVersion || result == pcpResultUnsuppVer {
+		return "", true, nil
+	}
+	if result != 0 {
+		return "", false, fmt.Errorf("NAT-PMP: el gateway respondió el código %d", result)
+	}
+	if len(resp) < 12 {
+		return "", false, fmt.Errorf("NAT-PMP: respuesta demasiado corta")
+	}
+	address := net.IP(resp[8:12])
+	if address.IsUnspecified() {
+		return "", false, fmt.Errorf("el router no tiene dirección WAN (¿sin conexión?)")
+	}
+	return address.String(), false, nil
+}
+
+// pcpExternalIP envía una petición MAP de PCP y retorna la dirección externa
+// asignada. El mapeo (del puerto local del socket, sin tráfico) se elimina después.
+func pcpExternalIP(ctx context.Context, conn net.Conn) (string, error) {
+	local, ok := conn.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return "", fmt.Errorf("PCP: dirección local desconocida")
+	}
+
+	var nonce [12]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return "", fmt.Errorf("PCP: %w", err)
+	}
+	req := pcpMapRequest(local, nonce, pcpMapLifetime)
+
+	resp, err := udpExchange(ctx, conn, req, func(b []byte) bool {
+		return isPCPMapResponse(b, nonce)
+	})
+	if err != nil {
+		return "", fmt.Errorf("PCP: %w", err)
+	}
+	ip, err := parsePCPMapResponse(resp)
+	if err != nil {
+		return "", err
+	}
+
+	// Eliminar el mapeo: misma petición con lifetime 0 (sin esperar respuesta)
+	_, _ = conn.Write(pcpMapRequest(local, nonce, 0))
+	return ip, nil
+}
+
+// isPCPMapResponse indica si el paquete es la respuesta MAP de PCP a la petición
+// con el nonce indicado
+func isPCPMapResponse(resp []byte, nonce [12]byte) bool {
+	return len(resp) >= 60 && resp[0] == pcpVersion && resp[1] == 0x80|pcpOpMap && string(resp[24:36]) == string(nonce[:])
+}
+
+// parsePCPMapResponse retorna la dirección IPv4 externa asignada en una respuesta
+// MAP de PCP (RFC 6887, secciones 7.2 y 11.1)
+func parsePCPMapResponse(resp []byte) (string, error) {
+	if len(resp) < 60 {
+		return "", fmt.Errorf("PCP: respuesta demasiado corta")
+	}
+	if code := resp[3]; code != 0 {
+		return "", fmt.Errorf("PCP: el gateway respondió el código %d", code)
+	}
+	ip := net.IP(resp[44:60])
+	if ip.To4() == nil || ip.IsUnspecified() {
+		return "", fmt.Errorf("PCP: el gateway no asignó una dirección IPv4 externa")
+	}
+	return ip.String(), nil
+}
+
+// pcpMapRequest arma una petición MAP de PCP (RFC 6887, secciones 7.1 y 11.1) para
+// UDP desde el puerto local, sin dirección ni puerto externo sugeridos
+func pcpMapRequest(local *net.UDPAddr, nonce [12]byte, lifetime uint32) []byte {
+	req := make([]byte, 60)
+	req[0] = pcpVersion
+	req[1] = pcpOpMap
+	binary.BigEndian.PutUint32(req[4:8], lifetime)
+	copy(req[8:24], local.IP.To16())
+
+	copy(req[24:36], nonce[:])
+	req[36] = 17 // UDP
+	binary.BigEndian.PutUint16(req[40:42], uint16(local.Port))
+	// Dirección externa sugerida: IPv4 sin especificar (::ffff:0.0.0.0)
+	req[54], req[55] = 0xff, 0xff
+	return req
+}
+
+// udpExchange envía la petición y espera una respuesta que acepte valid,
+// retransmitiendo con intervalos que se duplican desde 250 ms (RFC 6886) hasta
+// que se cancele ctx
+func udpExchange(ctx context.Context, conn net.Conn, req []byte, valid func([]byte) bool) ([]byte, error) {
+	buf := make([]byte, 1100)
+	interval := natpmpInitialInterval
+	for {
+		if _, err := conn.Write(req); err != nil {
+			return nil, err
+		}
+
+		wait := time.Now().Add(interval)
+		if deadline, ok := ctx.Deadline(); ok && deadline.Before(wait) {
+			wait = deadline
+		}
+		if err := conn.SetReadDeadline(wait); err != nil {
+			return nil, err
+		}
+
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				if !isTimeout(err) {
+					return nil, err
+				}
+				break
+			}
+			if valid(buf[:n]) {
+				return buf[:n], nil
+			}
+		}
+
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("el gateway no respondió: %w", ctx.Err())
+		}
+		if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+			return nil, fmt.Errorf("el gateway no respondió: %w", context.DeadlineExceeded)
+		}
+		interval *= 2
+	}
+}
+
+// defaultGateway lee el gateway IPv4 por defecto de la tabla de rutas de Linux
+func defaultGateway() (net.IP, error) {
+	f, err := os.Open(procNetRoute)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo leer el gateway por defecto (indícalo como destino): %w", err)
+	}
+	defer f.Close()
+
+	gw, err := parseDefaultGateway(f)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo %s: %w", procNetRoute, err)
+	}
+	if gw == nil {
+		return nil, errors.New("no hay gateway IPv4 por defecto")
+	}
+	return gw, nil
+}
+
+// parseDefaultGateway busca la ruta por defecto en una tabla con el formato de
+// /proc/net/route; retorna nil si no hay ninguna
+func parseDefaultGateway(r io.Reader) (net.IP, error) {
+	// Columnas: Iface Destination Gateway Flags ...; valores en hexadecimal little-endian
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[1] != "00000000" {
+			continue
+		}
+		flags, err := strconv.ParseUint(fields[3], 16, 32)
+		if err != nil || flags&0x2 == 0 { // RTF_GATEWAY
+			continue
+		}
+		gw, err := strconv.ParseUint(fields[2], 16, 32)
+		if err != nil || gw == 0 {
+			continue
+		}
+		return net.IPv4(byte(gw), byte(gw>>8), byte(gw>>16), byte(gw>>24)), nil
+	}
+	return nil, scanner.Err()
+}
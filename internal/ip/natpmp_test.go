@@ -0,0 +1,205 @@
+package ip
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestParseNATPMPResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    []byte
+		want    string
+		pcpOnly bool
+		wantErr string
+	}{
+		{
+			name: "dirección externa",
+			resp: []byte{0, 128, 0, 0, 0, 0, 0x12, 0x34, 203, 0, 113, 7},
+			want: "203.0.113.7",
+		},
+		{
+			name:    "servidor solo PCP (versión 2)",
+			resp:    []byte{2, 128, 0, 0},
+			pcpOnly: true,
+		},
+		{
+			name:    "UNSUPP_VERSION",
+			resp:    []byte{0, 128, 0, 1},
+			pcpOnly: true,
+		},
+		{
+			name:    "código de error",
+			resp:    []byte{0, 128, 0, 3, 0, 0, 0, 0, 0, 0, 0, 0},
+			wantErr: "código 3",
+		},
+		{
+			name:    "sin dirección WAN",
+			resp:    []byte{0, 128, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0},
+			wantErr: "no tiene dirección WAN",
+		},
+		{
+			name:    "truncada tras la cabecera",
+			resp:    []byte{0, 128, 0, 0, 0, 0, 0, 1},
+			wantErr: "demasiado corta",
+		},
+		{
+			name:    "truncada",
+			resp:    []byte{0, 128},
+			wantErr: "demasiado corta",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, pcpOnly, err := parseNATPMPResponse(tt.resp)
+			if pcpOnly != tt.pcpOnly {
+				t.Errorf("pcpOnly = %v, want %v", pcpOnly, tt.pcpOnly)
+			}
+			checkResult(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestParsePCPMapResponse(t *testing.T) {
+	var nonce [12]byte
+	copy(nonce[:], "orgmdns-test")
+
+	// response arma una respuesta MAP con el código y la dirección externa indicados
+	response := func(code byte, external net.IP) []byte {
+		resp := make([]byte, 60)
+		resp[0], resp[1], resp[3] = pcpVersion, 0x80|pcpOpMap, code
+		copy(resp[24:36], nonce[:])
+		copy(resp[44:60], external.To16())
+		return resp
+	}
+
+	tests := []struct {
+		name    string
+		resp    []byte
+		want    string
+		wantErr string
+	}{
+		{name: "dirección IPv4 externa", resp: response(0, net.ParseIP("198.51.100.20")), want: "198.51.100.20"},
+		{name: "código de error", resp: response(8, net.ParseIP("198.51.100.20")), wantErr: "código 8"},
+		{name: "dirección IPv6", resp: response(0, net.ParseIP("2001:db8::1")), wantErr: "IPv4 externa"},
+		{name: "IPv4 sin especificar", resp: response(0, net.IPv4zero), wantErr: "IPv4 externa"},
+		{name: "truncada", resp: response(0, net.ParseIP("198.51.100.20"))[:44], wantErr: "demasiado corta"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePCPMapResponse(tt.resp)
+			checkResult(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+
+	valid := response(0, net.ParseIP("198.51.100.20"))
+	if !isPCPMapResponse(valid, nonce) {
+		t.Error("isPCPMapResponse rechazó una respuesta válida")
+	}
+	other := nonce
+	other[0] ^= 0xff
+	if isPCPMapResponse(valid, other) {
+		t.Error("isPCPMapResponse aceptó una respuesta con otro nonce")
+	}
+	if isPCPMapResponse(valid[:59], nonce) {
+		t.Error("isPCPMapResponse aceptó una respuesta truncada")
+	}
+}
+
+func TestPCPMapRequest(t *testing.T) {
+	var nonce [12]byte
+	copy(nonce[:], "orgmdns-test")
+	local := &net.UDPAddr{IP: net.ParseIP("192.168.1.50"), Port: 40000}
+
+	req := pcpMapRequest(local, nonce, pcpMapLifetime)
+	if len(req) != 60 || req[0] != pcpVersion || req[1] != pcpOpMap {
+		t.Fatalf("cabecera inválida: % x", req[:4])
+	}
+	if got := net.IP(req[8:24]); !got.Equal(local.IP) {
+		t.Errorf("dirección del cliente = %v, want %v", got, local.IP)
+	}
+	if string(req[24:36]) != string(nonce[:]) {
+		t.Errorf("nonce = % x", req[24:36])
+	}
+	if req[36] != 17 || int(req[40])<<8|int(req[41]) != local.Port {
+		t.Errorf("protocolo/puerto = %d/%d", req[36], int(req[40])<<8|int(req[41]))
+	}
+
+	// La respuesta del gateway usa el mismo formato: el decodificador la acepta
+	resp := append([]byte{}, req...)
+	resp[1] |= 0x80
+	copy(resp[44:60], net.ParseIP("203.0.113.9").To16())
+	if got, err := parsePCPMapResponse(resp); err != nil || got != "203.0.113.9" {
+		t.Errorf("parsePCPMapResponse = %q, %v", got, err)
+	}
+}
+
+func TestParseDefaultGateway(t *testing.T) {
+	const header = "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n"
+
+	tests := []struct {
+		name  string
+		table string
+		want  string // "" = sin gateway
+	}{
+		{
+			name: "ruta por defecto",
+			table: header +
+				"eth0\t00000000\t0101A8C0\t0003\t0\t0\t100\t00000000\t0\t0\t0\n" +
+				"eth0\t0001A8C0\t00000000\t0001\t0\t0\t100\t00FFFFFF\t0\t0\t0\n",
+			want: "192.168.1.1",
+		},
+		{
+			name: "ignora rutas que no son por defecto",
+			table: header +
+				"eth0\t0001A8C0\t00000000\t0001\t0\t0\t100\t00FFFFFF\t0\t0\t0\n" +
+				"wg0\t00000000\t0100000A\t0003\t0\t0\t50\t00000000\t0\t0\t0\n",
+			want: "10.0.0.1",
+		},
+		{
+			name: "sin RTF_GATEWAY",
+			table: header +
+				"ppp0\t00000000\t00000000\t0001\t0\t0\t0\t00000000\t0\t0\t0\n",
+		},
+		{
+			name:  "tabla vacía",
+			table: header,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gw, err := parseDefaultGateway(strings.NewReader(tt.table))
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			got := ""
+			if gw != nil {
+				got = gw.String()
+			}
+			if got != tt.want {
+				t.Errorf("gateway = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// checkResult compara el resultado de un decodificador con el esperado
+func checkResult(t *testing.T, got string, err error, want, wantErr string) {
+	t.Helper()
+	if wantErr != "" {
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("error = %v, want que contenga %q", err, wantErr)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if got != want {
+		t.Errorf("IP = %q, want %q", got, want)
+	}
+}
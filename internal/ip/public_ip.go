@@ -2,6 +2,7 @@ package ip
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -11,25 +12,128 @@ import (
 	"github.com/pion/stun"
 )
 
-// GetPublicIP obtiene la IP pública usando STUN como método principal
-// y HTTP como fallback si STUN falla
-func GetPublicIP() (string, error) {
-	// Intentar primero con STUN
-	ip, err := getPublicIPSTUN()
-	if err == nil {
-		return ip, nil
+// Servidor STUN usado para ambas familias (Google responde por IPv4 e IPv6)
+const DefaultSTUNServer = "stun.l.google.com:19302"
+
+// Servicios HTTP que responden solo por IPv4
+var httpServicesV4 = []string{
+	"https://api.ipify.org?format=text",
+	"https://ipv4.icanhazip.com",
+	"https://ifconfig.me/ip",
+}
+
+// Servicios HTTP que responden solo por IPv6
+var httpServicesV6 = []string{
+	"https://api6.ipify.org?format=text",
+	"https://ipv6.icanhazip.com",
+	"https://v6.ident.me",
+}
+
+// Tiempo máximo de cada consulta si el contexto no trae deadline
+const defaultTimeout = 5 * time.Second
+
+// DefaultChain retorna la cadena de fuentes por defecto: STUN como método principal
+// y los servicios HTTP de cada familia como fallback
+func DefaultChain() *Chain {
+	return NewChain(DefaultEntries()...)
+}
+
+// DefaultEntries retorna las fuentes por defecto en orden: STUN y los servicios
+// HTTP de cada familia
+func DefaultEntries() []ChainEntry {
+	entries := []ChainEntry{{Source: NewSTUNSource(DefaultSTUNServer)}}
+	for _, url := range httpServicesV4 {
+		entries = append(entries, ChainEntry{Source: NewHTTPSource(url), Types: []string{RecordTypeA}})
+	}
+	for _, url := range httpServicesV6 {
+		entries = append(entries, ChainEntry{Source: NewHTTPSource(url), Types: []string{RecordTypeAAAA}})
+	}
+	return entries
+}
+
+// DefaultHTTPServices retorna los servicios HTTP por defecto de la familia del tipo de registro
+func DefaultHTTPServices(recordType string) []string {
+	if recordType == RecordTypeAAAA {
+		return append([]string(nil), httpServicesV6...)
+	}
+	return append([]string(nil), httpServicesV4...)
+}
+
+// STUNSource obtiene la IP pública con una petición Binding a un servidor STUN
+type STUNSource struct {
+	server string // host:puerto
+}
+
+// NewSTUNSource crea una fuente STUN (server vacío = DefaultSTUNServer)
+func NewSTUNSource(server string) *STUNSource {
+	if server == "" {
+		server = DefaultSTUNServer
+	}
+	return &STUNSource{server: server}
+}
+
+func (s *STUNSource) Name() string {
+	return "stun:" + s.server
+}
+
+// PublicIP consulta el servidor STUN por udp4 o udp6 según el tipo de registro
+func (s *STUNSource) PublicIP(ctx context.Context, recordType string) (string, error) {
+	network, err := networkFor(recordType, "udp")
+	if err != nil {
+		return "", err
+	}
+	return getPublicIPSTUN(ctx, network, s.server)
+}
+
+// HTTPSource obtiene la IP pública de un servicio HTTP que responde con la IP
+// en la primera línea
+type HTTPSource struct {
+	url string
+}
+
+// NewHTTPSource crea una fuente HTTP
+func NewHTTPSource(url string) *HTTPSource {
+	return &HTTPSource{url: url}
+}
+
+func (s *HTTPSource) Name() string {
+	return "http:" + s.url
+}
+
+// PublicIP consulta el servicio forzando la conexión por tcp4 o tcp6 según el tipo de registro
+func (s *HTTPSource) PublicIP(ctx context.Context, recordType string) (string, error) {
+	network, err := networkFor(recordType, "tcp")
+	if err != nil {
+		return "", err
 	}
 
-	// Fallback a HTTP
-	return getPublicIPHTTP()
+	var dialer net.Dialer
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+		},
+	}
+	return fetchIP(ctx, client, s.url)
 }
 
-// getPublicIPSTUN obtiene la IP pública usando STUN
-func getPublicIPSTUN() (string, error) {
-	c, err := stun.Dial("udp", "stun.l.google.com:19302")
+// getPublicIPSTUN obtiene la IP pública usando STUN por la red indicada (udp4 o udp6)
+func getPublicIPSTUN(ctx context.Context, network, server string) (string, error) {
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, server)
 	if err != nil {
 		return "", fmt.Errorf("error conectando a STUN: %w", err)
 	}
+
+	c, err := stun.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return "", fmt.Errorf("error conectando a STUN: %w", err)
+	}
 	defer c.Close()
 
 	message := stun.MustBuild(stun.TransactionID, stun.BindingRequest)
@@ -37,7 +141,7 @@ func getPublicIPSTUN() (string, error) {
 	var xorAddr stun.XORMappedAddress
 	done := make(chan error, 1)
 
-	c.Do(message, func(res stun.Event) {
+	err = c.Do(message, func(res stun.Event) {
 		if res.Error != nil {
 			done <- res.Error
 			return
@@ -48,52 +152,77 @@ func getPublicIPSTUN() (string, error) {
 		}
 		done <- nil
 	})
+	if err != nil {
+		return "", fmt.Errorf("error enviando petición STUN: %w", err)
+	}
 
-	// Timeout de 5 segundos
 	select {
 	case err := <-done:
 		if err != nil {
 			return "", fmt.Errorf("error en respuesta STUN: %w", err)
 		}
+		if !matchesNetwork(xorAddr.IP, network) {
+			return "", fmt.Errorf("STUN retornó una IP de otra familia: %s", xorAddr.IP)
+		}
 		return xorAddr.IP.String(), nil
-	case <-time.After(5 * time.Second):
-		return "", fmt.Errorf("timeout esperando respuesta STUN")
+	case <-ctx.Done():
+		return "", fmt.Errorf("timeout esperando respuesta STUN: %w", ctx.Err())
 	}
 }
 
-// getPublicIPHTTP obtiene la IP pública usando un servicio HTTP como fallback
-func getPublicIPHTTP() (string, error) {
-	client := &http.Client{
-		Timeout: 5 * time.Second,
+// fetchIP consulta un servicio HTTP que responde con la IP en la primera línea
+func fetchIP(ctx context.Context, client *http.Client, url string) (string, error) {
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creando request: %w", err)
 	}
 
-	// Intentar con varios servicios
-	services := []string{
-		"https://api.ipify.org?format=text",
-		"https://icanhazip.com",
-		"https://ifconfig.me/ip",
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error haciendo request: %w", err)
 	}
+	defer resp.Body.Close()
 
-	for _, url := range services {
-		resp, err := client.Get(url)
-		if err != nil {
-			continue
-		}
-		defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status %d", resp.StatusCode)
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			continue
+	scanner := bufio.NewScanner(resp.Body)
+	if scanner.Scan() {
+		ipStr := strings.TrimSpace(scanner.Text())
+		// Validar que sea una IP válida
+		if net.ParseIP(ipStr) != nil {
+			return ipStr, nil
 		}
+		return "", fmt.Errorf("respuesta no es una IP: %q", truncate(ipStr, 64))
+	}
+	return "", fmt.Errorf("respuesta vacía")
+}
 
-		scanner := bufio.NewScanner(resp.Body)
-		if scanner.Scan() {
-			ipStr := strings.TrimSpace(scanner.Text())
-			// Validar que sea una IP válida
-			if net.ParseIP(ipStr) != nil {
-				return ipStr, nil
-			}
-		}
+// withDefaultTimeout aplica defaultTimeout si el contexto no trae deadline
+func withDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
 	}
+	return context.WithTimeout(ctx, defaultTimeout)
+}
 
-	return "", fmt.Errorf("no se pudo obtener IP pública desde ningún servicio HTTP")
+// matchesNetwork indica si la IP pertenece a la familia de la red (sufijo 4 o 6)
+func matchesNetwork(ip net.IP, network string) bool {
+	isV4 := ip.To4() != nil
+	if strings.HasSuffix(network, "6") {
+		return !isV4
+	}
+	return isV4
+}
+
+// truncate recorta s a max bytes
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "..."
 }
@@ -0,0 +1,157 @@
+package ip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// Tipos de registro DNS (familias de IP) que atienden las fuentes
+const (
+	RecordTypeA    = "A"    // IPv4
+	RecordTypeAAAA = "AAAA" // IPv6
+)
+
+// IPSource es una fuente de la IP pública de una familia (A -> IPv4, AAAA -> IPv6)
+type IPSource interface {
+	// Name identifica la fuente en los logs (por ejemplo "stun:servidor:puerto")
+	Name() string
+	// PublicIP retorna la IP pública de la familia del tipo de registro
+	PublicIP(ctx context.Context, recordType string) (string, error)
+}
+
+// ChainEntry es una fuente de una Chain con su timeout y las familias que atiende
+type ChainEntry struct {
+	Source  IPSource
+	Timeout time.Duration // tiempo máximo de la consulta, 0 = defaultTimeout
+	Types   []string      // tipos de registro que atiende, vacío = ambos
+}
+
+// Handles indica si la fuente atiende el tipo de registro indicado
+func (e ChainEntry) Handles(recordType string) bool {
+	if len(e.Types) == 0 {
+		return true
+	}
+	for _, t := range e.Types {
+		if t == recordType {
+			return true
+		}
+	}
+	return false
+}
+
+// Chain prueba sus fuentes en orden y retorna la primera IP válida de la familia pedida
+type Chain struct {
+	entries []ChainEntry
+}
+
+var _ IPSource = (*Chain)(nil)
+
+// NewChain crea una cadena de fuentes que se prueban en el orden indicado
+func NewChain(entries ...ChainEntry) *Chain {
+	return &Chain{entries: entries}
+}
+
+func (c *Chain) Name() string {
+	names := make([]string, 0, len(c.entries))
+	for _, entry := range c.entries {
+		names = append(names, entry.Source.Name())
+	}
+	return "chain(" + strings.Join(names, ", ") + ")"
+}
+
+// PublicIP consulta cada fuente que atiende la familia, con su propio timeout, hasta
+// obtener una IP válida. Si todas fallan retorna los errores de cada una.
+func (c *Chain) PublicIP(ctx context.Context, recordType string) (string, error) {
+	if _, err := networkFor(recordType, "ip"); err != nil {
+		return "", err
+	}
+
+	var errs []error
+	for _, entry := range c.entries {
+		if !entry.Handles(recordType) {
+			continue
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		ip, err := querySource(ctx, entry, recordType)
+		if err == nil {
+			return ip, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", entry.Source.Name(), err))
+	}
+
+	if len(errs) == 0 {
+		return "", fmt.Errorf("ninguna fuente de IP configurada para %s", familyName(recordType))
+	}
+	return "", fmt.Errorf("no se pudo obtener IP pública %s desde ninguna fuente: %w", familyName(recordType), errors.Join(errs...))
+}
+
+// querySource consulta una fuente con su timeout y valida la familia de la respuesta
+// y que la IP sea pública; una IP privada, bogon o CGNAT es un fallo de la fuente
+func querySource(ctx context.Context, entry ChainEntry, recordType string) (string, error) {
+	ip, err := queryAddress(ctx, entry, recordType)
+	if err != nil {
+		return "", err
+	}
+	if err := CheckPublic(ip); err != nil {
+		return "", err
+	}
+	return ip, nil
+}
+
+// queryAddress consulta una fuente con su timeout y valida solo la familia de la
+// respuesta (la dirección WAN de un router puede no ser pública)
+func queryAddress(ctx context.Context, entry ChainEntry, recordType string) (string, error) {
+	timeout := entry.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ipStr, err := entry.Source.PublicIP(ctx, recordType)
+	if err != nil {
+		return "", err
+	}
+	return validateFamily(ipStr, recordType)
+}
+
+// validateFamily interpreta la IP y comprueba que sea de la familia del tipo de registro
+func validateFamily(ipStr, recordType string) (string, error) {
+	ip := net.ParseIP(strings.TrimSpace(ipStr))
+	if ip == nil {
+		return "", fmt.Errorf("respuesta no es una IP: %q", truncate(ipStr, 64))
+	}
+	network, err := networkFor(recordType, "ip")
+	if err != nil {
+		return "", err
+	}
+	if !matchesNetwork(ip, network) {
+		return "", fmt.Errorf("la fuente retornó una IP de otra familia: %s", ip)
+	}
+	return ip.String(), nil
+}
+
+// networkFor retorna la red de la familia del tipo de registro ("udp" -> "udp4"/"udp6")
+func networkFor(recordType, proto string) (string, error) {
+	switch recordType {
+	case RecordTypeA:
+		return proto + "4", nil
+	case RecordTypeAAAA:
+		return proto + "6", nil
+	}
+	return "", fmt.Errorf("tipo de registro no soportado: %s", recordType)
+}
+
+// familyName retorna el nombre de la familia de IP del tipo de registro
+func familyName(recordType string) string {
+	if recordType == RecordTypeAAAA {
+		return "IPv6"
+	}
+	return "IPv4"
+}
@@ -0,0 +1,314 @@
+package ip
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Dirección multicast de SSDP (descubrimiento UPnP)
+const ssdpAddr = "239.255.255.250:1900"
+
+// Tipos de dispositivo que se buscan con SSDP (IGD v1 y v2)
+var igdDeviceTypes = []string{
+	"urn:schemas-upnp-org:device:InternetGatewayDevice:1",
+	"urn:schemas-upnp-org:device:InternetGatewayDevice:2",
+}
+
+// Prefijos de los servicios WAN que implementan GetExternalIPAddress
+var wanServicePrefixes = []string{
+	"urn:schemas-upnp-org:service:WANIPConnection:",
+	"urn:schemas-upnp-org:service:WANPPPConnection:",
+}
+
+// Tamaño máximo de las descripciones y respuestas SOAP del router
+const maxUPnPResponse = 1 << 20
+
+// UPnPSource obtiene la dirección WAN del router con GetExternalIPAddress de UPnP
+// IGD. Descubre el router con SSDP (o usa la URL de su descripción) y recuerda el
+// servicio WAN encontrado hasta que deje de responder. Solo informa IPv4.
+type UPnPSource struct {
+	location string // URL de la descripción del dispositivo, vacío = SSDP
+	client   *http.Client
+
+	mu      sync.Mutex
+	service *upnpService // servicio WAN descubierto
+}
+
+// upnpService es un servicio WAN del router con su URL de control ya resuelta
+type upnpService struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+}
+
+// upnpDevice es un dispositivo de la descripción UPnP con sus subdispositivos
+type upnpDevice struct {
+	Services []upnpService `xml:"serviceList>service"`
+	Devices  []upnpDevice  `xml:"deviceList>device"`
+}
+
+// upnpRoot es la raíz de la descripción del dispositivo
+type upnpRoot struct {
+	URLBase string     `xml:"URLBase"`
+	Device  upnpDevice `xml:"device"`
+}
+
+// NewUPnPSource crea una fuente UPnP IGD. location es la URL de la descripción del
+// router (por ejemplo http://192.168.1.1:5000/rootDesc.xml); vacío = descubrir con SSDP.
+func NewUPnPSource(location string) *UPnPSource {
+	return &UPnPSource{location: location, client: &http.Client{}}
+}
+
+func (s *UPnPSource) Name() string {
+	if s.location == "" {
+		return "upnp"
+	}
+	return "upnp:" + s.location
+}
+
+// PublicIP retorna la dirección WAN que informa el router
+func (s *UPnPSource) PublicIP(ctx context.Context, recordType string) (string, error) {
+	if recordType != RecordTypeA {
+		return "", fmt.Errorf("UPnP IGD solo informa la dirección IPv4 externa")
+	}
+
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.service != nil {
+		ip, err := s.externalIP(ctx, s.service)
+		if err == nil {
+			return ip, nil
+		}
+		// El router pudo reiniciarse o cambiar de puerto: volver a descubrirlo
+		s.service = nil
+	}
+
+	locations := []string{s.location}
+	if s.location == "" {
+		var err error
+		if locations, err = discoverIGD(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	var errs []error
+	for _, location := range locations {
+		service, err := s.wanService(ctx, location)
+		if err == nil {
+			var ip string
+			if ip, err = s.externalIP(ctx, service); err == nil {
+				s.service = service
+				return ip, nil
+			}
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", location, err))
+	}
+	return "", fmt.Errorf("ningún router UPnP informó la dirección WAN: %w", errors.Join(errs...))
+}
+
+// discoverIGD busca routers UPnP IGD con SSDP y retorna la URL de la descripción
+// de cada uno (sin repetir), en el orden en que respondieron
+func discoverIGD(ctx context.Context) ([]string, error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo socket SSDP: %w", err)
+	}
+	defer conn.Close()
+
+	dst, err := net.ResolveUDPAddr("udp4", ssdpAddr)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range igdDeviceTypes {
+		msg := "M-SEARCH * HTTP/1.1\r\n" +
+			"HOST: " + ssdpAddr + "\r\n" +
+			"MAN: \"ssdp:discover\"\r\n" +
+			"MX: 2\r\n" +
+			"ST: " + st + "\r\n\r\n"
+		if _, err := conn.WriteTo([]byte(msg), dst); err != nil {
+			return nil, fmt.Errorf("error enviando búsqueda SSDP: %w", err)
+		}
+	}
+
+	// Los routers responden durante MX segundos; se espera hasta la primera respuesta
+	// y se recogen las que lleguen poco después
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var locations []string
+	seen := make(map[string]bool)
+	buf := make([]byte, 2048)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if len(locations) > 0 {
+				return locations, nil
+			}
+			if ctx.Err() != nil || isTimeout(err) {
+				return nil, fmt.Errorf("ningún router respondió a la búsqueda UPnP (SSDP)")
+			}
+			return nil, fmt.Errorf("error leyendo respuestas SSDP: %w", err)
+		}
+
+		resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buf[:n])), nil)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		location := resp.Header.Get("Location")
+		if location == "" || seen[location] {
+			continue
+		}
+		seen[location] = true
+		locations = append(locations, location)
+
+		if len(locations) == 1 {
+			if err := conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond)); err != nil {
+				return locations, nil
+			}
+		}
+	}
+}
+
+// wanService descarga la descripción del dispositivo y busca un servicio WAN
+func (s *UPnPSource) wanService(ctx context.Context, location string) (*upnpService, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("URL de descripción inválida: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error descargando la descripción: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("descripción: status %d", resp.StatusCode)
+	}
+
+	var root upnpRoot
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, maxUPnPResponse)).Decode(&root); err != nil {
+		return nil, fmt.Errorf("error leyendo la descripción: %w", err)
+	}
+
+	service := findWANService(root.Device)
+	if service == nil {
+		return nil, fmt.Errorf("el dispositivo no tiene un servicio WANIPConnection ni WANPPPConnection")
+	}
+
+	base := location
+	if root.URLBase != "" {
+		base = root.URLBase
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("URL base inválida: %w", err)
+	}
+	controlURL, err := baseURL.Parse(strings.TrimSpace(service.ControlURL))
+	if err != nil {
+		return nil, fmt.Errorf("URL de control inválida: %w", err)
+	}
+	return &upnpService{ServiceType: strings.TrimSpace(service.ServiceType), ControlURL: controlURL.String()}, nil
+}
+
+// findWANService busca en profundidad el primer servicio WAN del dispositivo
+func findWANService(device upnpDevice) *upnpService {
+	for i, service := range device.Services {
+		for _, prefix := range wanServicePrefixes {
+			if strings.HasPrefix(strings.TrimSpace(service.ServiceType), prefix) {
+				return &device.Services[i]
+			}
+		}
+	}
+	for _, child := range device.Devices {
+		if service := findWANService(child); service != nil {
+			return service
+		}
+	}
+	return nil
+}
+
+// externalIP llama a GetExternalIPAddress en el servicio WAN
+func (s *UPnPSource) externalIP(ctx context.Context, service *upnpService) (string, error) {
+	body := `<?xml version="1.0"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">` +
+		`<s:Body><u:GetExternalIPAddress xmlns:u="` + service.ServiceType + `"/></s:Body></s:Envelope>`
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, service.ControlURL, strings.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("error creando request SOAP: %w", err)
+	}
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", `"`+service.ServiceType+`#GetExternalIPAddress"`)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error llamando GetExternalIPAddress: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxUPnPResponse))
+	if err != nil {
+		return "", fmt.Errorf("error leyendo la respuesta SOAP: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// Los errores UPnP llegan como SOAP fault con errorDescription
+		if description, err := soapValue(bytes.NewReader(data), "errorDescription"); err == nil && description != "" {
+			return "", fmt.Errorf("GetExternalIPAddress: status %d: %s", resp.StatusCode, description)
+		}
+		return "", fmt.Errorf("GetExternalIPAddress: status %d", resp.StatusCode)
+	}
+
+	ip, err := soapValue(bytes.NewReader(data), "NewExternalIPAddress")
+	if err != nil {
+		return "", fmt.Errorf("error leyendo la respuesta SOAP: %w", err)
+	}
+	if ip == "" || ip == "0.0.0.0" {
+		return "", fmt.Errorf("el router no tiene dirección WAN (¿sin conexión?)")
+	}
+	return ip, nil
+}
+
+// soapValue retorna el texto del primer elemento con el nombre local indicado
+func soapValue(r io.Reader, name string) (string, error) {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				return "", fmt.Errorf("la respuesta no contiene %s", name)
+			}
+			return "", err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != name {
+			continue
+		}
+		var value string
+		if err := decoder.DecodeElement(&value, &start); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(value), nil
+	}
+}
+
+// isTimeout indica si el error es un timeout de red
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
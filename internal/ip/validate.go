@@ -0,0 +1,111 @@
+package ip
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// Categorías de dirección rechazada. Se comprueban con errors.Is sobre el error de
+// una fuente; AddressError tiene el detalle (IP y rango).
+var (
+	ErrNotPublic = errors.New("la IP detectada no es pública")
+	ErrCGNAT     = errors.New("la IP detectada es de carrier-grade NAT")
+)
+
+// AddressError indica que una fuente respondió una dirección que no es alcanzable
+// desde internet (privada, loopback, link-local, documentación, bogon o CGNAT)
+type AddressError struct {
+	IP    string
+	Range string // rango y descripción, por ejemplo "192.168.0.0/16 (privada, RFC 1918)"
+	CGNAT bool   // 100.64.0.0/10: el proveedor de internet comparte la IP pública
+}
+
+func (e *AddressError) Error() string {
+	if e.CGNAT {
+		return fmt.Sprintf("%s está en %s: el proveedor de internet usa carrier-grade NAT y la dirección no es pública", e.IP, e.Range)
+	}
+	return fmt.Sprintf("%s no es una IP pública: %s", e.IP, e.Range)
+}
+
+// Is permite usar errors.Is(err, ErrNotPublic) y errors.Is(err, ErrCGNAT)
+func (e *AddressError) Is(target error) bool {
+	return target == ErrNotPublic || (e.CGNAT && target == ErrCGNAT)
+}
+
+// reservedRange es un rango que nunca debe publicarse como IP pública
+type reservedRange struct {
+	network     *net.IPNet
+	description string
+}
+
+// cgnatRange es el espacio compartido de carrier-grade NAT (RFC 6598)
+const cgnatRange = "100.64.0.0/10"
+
+// reservedRanges son los rangos privados, especiales y bogon de IPv4 e IPv6
+var reservedRanges = mustParseRanges(map[string]string{
+	"0.0.0.0/8":       "red actual, RFC 1122",
+	"10.0.0.0/8":      "privada, RFC 1918",
+	cgnatRange:        "carrier-grade NAT, RFC 6598",
+	"127.0.0.0/8":     "loopback, RFC 1122",
+	"169.254.0.0/16":  "link-local, RFC 3927",
+	"172.16.0.0/12":   "privada, RFC 1918",
+	"192.0.0.0/24":    "asignaciones de protocolo IETF, RFC 6890",
+	"192.0.2.0/24":    "documentación TEST-NET-1, RFC 5737",
+	"192.88.99.0/24":  "relay 6to4 obsoleto, RFC 7526",
+	"192.168.0.0/16":  "privada, RFC 1918",
+	"198.18.0.0/15":   "pruebas de rendimiento, RFC 2544",
+	"198.51.100.0/24": "documentación TEST-NET-2, RFC 5737",
+	"203.0.113.0/24":  "documentación TEST-NET-3, RFC 5737",
+	"224.0.0.0/4":     "multicast, RFC 5771",
+	"240.0.0.0/4":     "reservada, RFC 1112",
+	"::/128":          "sin especificar, RFC 4291",
+	"::1/128":         "loopback, RFC 4291",
+	"64:ff9b::/96":    "NAT64, RFC 6052",
+	"64:ff9b:1::/48":  "NAT64 local, RFC 8215",
+	"100::/64":        "descarte, RFC 6666",
+	"2001:db8::/32":   "documentación, RFC 3849",
+	"2001:10::/28":    "ORCHID obsoleto, RFC 4843",
+	"2002::/16":       "6to4, RFC 3056",
+	"3fff::/20":       "documentación, RFC 9637",
+	"fc00::/7":        "única local ULA, RFC 4193",
+	"fe80::/10":       "link-local, RFC 4291",
+	"fec0::/10":       "site-local obsoleta, RFC 3879",
+	"ff00::/8":        "multicast, RFC 4291",
+})
+
+// CheckPublic retorna un *AddressError si la IP no es alcanzable desde internet.
+// En IPv6 solo se acepta el espacio unicast global 2000::/3.
+func CheckPublic(address string) error {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return fmt.Errorf("respuesta no es una IP: %q", truncate(address, 64))
+	}
+
+	for _, r := range reservedRanges {
+		if r.network.Contains(ip) {
+			prefix := r.network.String()
+			return &AddressError{IP: ip.String(), Range: prefix + " (" + r.description + ")", CGNAT: prefix == cgnatRange}
+		}
+	}
+	if ip.To4() == nil && !globalUnicastV6.Contains(ip) {
+		return &AddressError{IP: ip.String(), Range: "fuera de 2000::/3 (no asignada como unicast global)"}
+	}
+	return nil
+}
+
+// globalUnicastV6 es el espacio IPv6 asignado como unicast global (RFC 4291)
+var globalUnicastV6 = mustParseRanges(map[string]string{"2000::/3": ""})[0].network
+
+// mustParseRanges interpreta los rangos reservados (los prefijos son constantes válidas)
+func mustParseRanges(ranges map[string]string) []reservedRange {
+	parsed := make([]reservedRange, 0, len(ranges))
+	for prefix, description := range ranges {
+		_, network, err := net.ParseCIDR(prefix)
+		if err != nil {
+			panic(fmt.Sprintf("rango inválido %s: %v", prefix, err))
+		}
+		parsed = append(parsed, reservedRange{network: network, description: description})
+	}
+	return parsed
+}
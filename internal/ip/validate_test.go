@@ -0,0 +1,90 @@
+package ip
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckPublic(t *testing.T) {
+	tests := []struct {
+		address   string
+		public    bool
+		cgnat     bool
+		wantRange string // prefijo esperado en AddressError.Range
+	}{
+		{address: "8.8.8.8", public: true},
+		{address: "1.1.1.1", public: true},
+		{address: "100.63.255.255", public: true},
+		{address: "100.128.0.0", public: true},
+		{address: "172.32.0.1", public: true},
+		{address: "2606:4700:4700::1111", public: true},
+		{address: "2a00:1450:4001:82a::200e", public: true},
+
+		{address: "10.1.2.3", wantRange: "10.0.0.0/8"},
+		{address: "172.16.0.1", wantRange: "172.16.0.0/12"},
+		{address: "192.168.1.10", wantRange: "192.168.0.0/16"},
+		{address: "127.0.0.1", wantRange: "127.0.0.0/8"},
+		{address: "169.254.10.20", wantRange: "169.254.0.0/16"},
+		{address: "0.1.2.3", wantRange: "0.0.0.0/8"},
+		{address: "192.0.2.1", wantRange: "192.0.2.0/24"},
+		{address: "198.51.100.1", wantRange: "198.51.100.0/24"},
+		{address: "203.0.113.1", wantRange: "203.0.113.0/24"},
+		{address: "198.18.0.1", wantRange: "198.18.0.0/15"},
+		{address: "224.0.0.1", wantRange: "224.0.0.0/4"},
+		{address: "255.255.255.255", wantRange: "240.0.0.0/4"},
+		{address: "100.64.0.1", cgnat: true, wantRange: "100.64.0.0/10"},
+		{address: "100.127.255.254", cgnat: true, wantRange: "100.64.0.0/10"},
+		{address: "::ffff:192.168.1.1", wantRange: "192.168.0.0/16"},
+
+		{address: "::1", wantRange: "::1/128"},
+		{address: "::", wantRange: "::/128"},
+		{address: "fe80::1", wantRange: "fe80::/10"},
+		{address: "fd00::1", wantRange: "fc00::/7"},
+		{address: "2001:db8::1", wantRange: "2001:db8::/32"},
+		{address: "2002:c000:0204::1", wantRange: "2002::/16"},
+		{address: "64:ff9b::808:808", wantRange: "64:ff9b::/96"},
+		{address: "ff02::1", wantRange: "ff00::/8"},
+		{address: "4000::1", wantRange: "fuera de 2000::/3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := CheckPublic(tt.address)
+			if tt.public {
+				if err != nil {
+					t.Fatalf("CheckPublic(%q) = %v, want nil", tt.address, err)
+				}
+				return
+			}
+
+			var addrErr *AddressError
+			if !errors.As(err, &addrErr) {
+				t.Fatalf("CheckPublic(%q) = %v, want *AddressError", tt.address, err)
+			}
+			if !strings.HasPrefix(addrErr.Range, tt.wantRange) {
+				t.Errorf("Range = %q, want prefijo %q", addrErr.Range, tt.wantRange)
+			}
+			if !errors.Is(err, ErrNotPublic) {
+				t.Errorf("errors.Is(err, ErrNotPublic) = false")
+			}
+			if addrErr.CGNAT != tt.cgnat || errors.Is(err, ErrCGNAT) != tt.cgnat {
+				t.Errorf("CGNAT = %v, errors.Is(err, ErrCGNAT) = %v, want %v", addrErr.CGNAT, errors.Is(err, ErrCGNAT), tt.cgnat)
+			}
+		})
+	}
+}
+
+func TestCheckPublicInvalid(t *testing.T) {
+	for _, address := range []string{"", "not-an-ip", "203.0.113", "<html>"} {
+		err := CheckPublic(address)
+		if err == nil {
+			t.Errorf("CheckPublic(%q) = nil, want error", address)
+			continue
+		}
+		var addrErr *AddressError
+		if errors.As(err, &addrErr) {
+			t.Errorf("CheckPublic(%q) = %v, want un error que no sea *AddressError", address, err)
+		}
+	}
+}
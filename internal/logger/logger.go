@@ -16,6 +16,12 @@ type Logger struct {
 }
 
 func Init(debug bool) *Logger {
+	return InitWithConsole(debug, os.Stdout)
+}
+
+// InitWithConsole es como Init pero escribe la salida de consola en console
+// (por ejemplo stderr, para dejar stdout libre para el plan del dry-run)
+func InitWithConsole(debug bool, console io.Writer) *Logger {
 	// Configurar nivel de log
 	level := slog.LevelInfo
 	if debug {
@@ -29,7 +35,7 @@ func Init(debug bool) *Logger {
 	}
 
 	var logFile *os.File
-	var multiWriter io.Writer = os.Stdout
+	var multiWriter io.Writer = console
 
 	// Intentar crear directorio y archivo de log
 	// Si falla, solo usaremos stdout (no hacemos panic)
@@ -42,7 +48,7 @@ func Init(debug bool) *Logger {
 		)
 		if err == nil {
 			logFile = file
-			multiWriter = io.MultiWriter(os.Stdout, logFile)
+			multiWriter = io.MultiWriter(console, logFile)
 		} else {
 			// Si no puede escribir al archivo, solo usar stdout
 			fmt.Fprintf(os.Stderr, "Warning: No se pudo abrir archivo de log (%s), usando solo stdout: %v\n", logPath, err)
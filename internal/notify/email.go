@@ -1,11 +1,17 @@
 package notify
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
+	"net"
 	"net/smtp"
 	"time"
 )
 
+// Tiempo máximo de envío de un correo si el contexto no trae deadline
+const sendTimeout = 30 * time.Second
+
 type EmailNotifier struct {
 	from     string
 	to       string
@@ -25,56 +31,194 @@ func NewEmailNotifier(from, to, password, smtpHost, smtpPort string) *EmailNotif
 }
 
 // SendDNSUpdateNotification envía un correo notificando el cambio de IP en un registro DNS
-func (e *EmailNotifier) SendDNSUpdateNotification(recordName, oldIP, newIP string) error {
-	subject := fmt.Sprintf("[orgmdns] DNS actualizado: %s", recordName)
+// (un correo por familia: A para IPv4, AAAA para IPv6). changes lista otros ajustes
+// corregidos en la misma actualización (TTL, proxied, comentario, etiquetas).
+func (e *EmailNotifier) SendDNSUpdateNotification(ctx context.Context, recordName, recordType, oldIP, newIP string, changes []string) error {
+	subject := fmt.Sprintf("[orgmdns] DNS actualizado: %s (%s)", recordName, recordType)
+
+	otherChanges := ""
+	if len(changes) > 0 {
+		otherChanges = "\nOtros ajustes corregidos:\n" + formatList(changes) + "\n"
+	}
+
 	body := fmt.Sprintf(`Hola,
 
 El registro DNS ha sido actualizado automáticamente por orgmdns.
 
 Detalles:
 - Registro: %s
+- Tipo: %s
 - IP anterior: %s
 - IP nueva: %s
 - Fecha/hora: %s
+%s
+Este es un mensaje automático, por favor no respondas.
+
+--
+orgmdns
+`, recordName, recordType, oldIP, newIP, time.Now().Format("2006-01-02 15:04:05 MST"), otherChanges)
+
+	if err := e.send(ctx, subject, body); err != nil {
+		return fmt.Errorf("error enviando correo: %w", err)
+	}
+
+	return nil
+}
+
+// SendDNSSettingsNotification envía un correo notificando la corrección de ajustes
+// de un registro DNS (TTL, proxied, comentario, etiquetas) sin cambio de IP
+func (e *EmailNotifier) SendDNSSettingsNotification(ctx context.Context, recordName, recordType string, changes []string) error {
+	subject := fmt.Sprintf("[orgmdns] DNS corregido: %s (%s)", recordName, recordType)
+	body := fmt.Sprintf(`Hola,
+
+Los ajustes del registro DNS no coincidían con la configuración y han sido corregidos por orgmdns.
+
+Detalles:
+- Registro: %s
+- Tipo: %s
+- Fecha/hora: %s
+
+Ajustes corregidos:
+%s
 
 Este es un mensaje automático, por favor no respondas.
 
 --
 orgmdns
-`, recordName, oldIP, newIP, time.Now().Format("2006-01-02 15:04:05 MST"))
+`, recordName, recordType, time.Now().Format("2006-01-02 15:04:05 MST"), formatList(changes))
 
-	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s", e.from, e.to, subject, body)
+	if err := e.send(ctx, subject, body); err != nil {
+		return fmt.Errorf("error enviando correo de ajustes: %w", err)
+	}
+
+	return nil
+}
 
-	auth := smtp.PlainAuth("", e.from, e.password, e.smtpHost)
+// SendDNSCreateNotification envía un correo notificando la creación de un registro DNS inexistente
+func (e *EmailNotifier) SendDNSCreateNotification(ctx context.Context, recordName, recordType, ip string) error {
+	subject := fmt.Sprintf("[orgmdns] DNS creado: %s (%s)", recordName, recordType)
+	body := fmt.Sprintf(`Hola,
 
-	addr := fmt.Sprintf("%s:%s", e.smtpHost, e.smtpPort)
-	err := smtp.SendMail(addr, auth, e.from, []string{e.to}, []byte(message))
-	if err != nil {
-		return fmt.Errorf("error enviando correo: %w", err)
+El registro DNS no existía y ha sido creado automáticamente por orgmdns.
+
+Detalles:
+- Registro: %s
+- Tipo: %s
+- IP: %s
+- Fecha/hora: %s
+
+Este es un mensaje automático, por favor no respondas.
+
+--
+orgmdns
+`, recordName, recordType, ip, time.Now().Format("2006-01-02 15:04:05 MST"))
+
+	if err := e.send(ctx, subject, body); err != nil {
+		return fmt.Errorf("error enviando correo de creación: %w", err)
+	}
+
+	return nil
+}
+
+// SendIPListUpdateNotification envía un correo notificando que se reemplazó la IP
+// pública en una lista de IPs (por ejemplo, una lista de Cloudflare usada en reglas WAF)
+func (e *EmailNotifier) SendIPListUpdateNotification(ctx context.Context, listName, recordType, added string, removed []string) error {
+	subject := fmt.Sprintf("[orgmdns] Lista de IPs actualizada: %s (%s)", listName, recordType)
+
+	if added == "" {
+		added = "(ya estaba en la lista)"
+	}
+	removedList := "(ninguna)"
+	if len(removed) > 0 {
+		removedList = "\n" + formatList(removed)
+	}
+
+	body := fmt.Sprintf(`Hola,
+
+La lista de IPs ha sido actualizada automáticamente por orgmdns con la IP pública actual.
+
+Detalles:
+- Lista: %s
+- Tipo: %s
+- Agregada: %s
+- Eliminadas: %s
+- Fecha/hora: %s
+
+Este es un mensaje automático, por favor no respondas.
+
+--
+orgmdns
+`, listName, recordType, added, removedList, time.Now().Format("2006-01-02 15:04:05 MST"))
+
+	if err := e.send(ctx, subject, body); err != nil {
+		return fmt.Errorf("error enviando correo de lista de IPs: %w", err)
+	}
+
+	return nil
+}
+
+// SendUnreachableNotification envía un correo avisando que la IP detectada no es
+// pública (privada, bogon o carrier-grade NAT), por lo que el sitio no es alcanzable
+// desde internet y los registros DNS no se actualizan
+func (e *EmailNotifier) SendUnreachableNotification(ctx context.Context, recordType, address, reason string, cgnat bool) error {
+	subject := fmt.Sprintf("[orgmdns] Sitio no alcanzable desde internet (%s)", recordType)
+
+	explanation := "La IP detectada no es una dirección pública, así que el sitio ya no es alcanzable desde internet."
+	action := "Revisa la conexión del router y las fuentes de IP configuradas."
+	if cgnat {
+		explanation = "El proveedor de internet está usando carrier-grade NAT (CGNAT): la conexión comparte su IP pública con otros clientes y el sitio ya no es alcanzable desde internet."
+		action = "Solicita al proveedor de internet una IP pública (o usa IPv6 o un túnel)."
+	}
+
+	body := fmt.Sprintf(`Hola,
+
+%s
+
+Los registros DNS conservan su valor actual: orgmdns no publica direcciones que no sean públicas.
+
+Detalles:
+- Tipo: %s
+- IP detectada: %s
+- Motivo: %s
+- Fecha/hora: %s
+
+%s
+
+Este es un mensaje automático, por favor no respondas.
+
+--
+orgmdns
+`, explanation, recordType, address, reason, time.Now().Format("2006-01-02 15:04:05 MST"), action)
+
+	if err := e.send(ctx, subject, body); err != nil {
+		return fmt.Errorf("error enviando correo de sitio no alcanzable: %w", err)
 	}
 
 	return nil
 }
 
-// SendStartupNotification envía un correo cuando inicia el verificador DNS
-func (e *EmailNotifier) SendStartupNotification(currentIP string, recordNames []string) error {
+// SendStartupNotification envía un correo cuando inicia el verificador DNS.
+// currentIPv4 o currentIPv6 pueden estar vacíos si esa familia no se gestiona o no se detectó.
+func (e *EmailNotifier) SendStartupNotification(ctx context.Context, currentIPv4, currentIPv6 string, recordNames []string) error {
 	subject := "[orgmdns] Verificador DNS corriendo"
 	
 	// Formatear lista de subdominios
-	recordsList := ""
-	for i, name := range recordNames {
-		recordsList += fmt.Sprintf("- %s", name)
-		if i < len(recordNames)-1 {
-			recordsList += "\n"
-		}
-	}
+	recordsList := formatList(recordNames)
 	
+	if currentIPv4 == "" {
+		currentIPv4 = "no detectada"
+	}
+	if currentIPv6 == "" {
+		currentIPv6 = "no detectada"
+	}
+
 	body := fmt.Sprintf(`Hola,
 
 El verificador DNS ha iniciado correctamente.
 
 Detalles:
-- IP pública detectada: %s
+- IP pública IPv4 detectada: %s
+- IP pública IPv6 detectada: %s
 - Fecha/hora de inicio: %s
 - Subdominios configurados:
 %s
@@ -85,15 +229,9 @@ Este es un mensaje automático, por favor no respondas.
 
 --
 orgmdns
-`, currentIP, time.Now().Format("2006-01-02 15:04:05 MST"), recordsList)
-
-	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s", e.from, e.to, subject, body)
-
-	auth := smtp.PlainAuth("", e.from, e.password, e.smtpHost)
+`, currentIPv4, currentIPv6, time.Now().Format("2006-01-02 15:04:05 MST"), recordsList)
 
-	addr := fmt.Sprintf("%s:%s", e.smtpHost, e.smtpPort)
-	err := smtp.SendMail(addr, auth, e.from, []string{e.to}, []byte(message))
-	if err != nil {
+	if err := e.send(ctx, subject, body); err != nil {
 		return fmt.Errorf("error enviando correo de inicio: %w", err)
 	}
 
@@ -101,7 +239,7 @@ orgmdns
 }
 
 // SendConnectionRestoredNotification envía un correo cuando se restaura la conexión
-func (e *EmailNotifier) SendConnectionRestoredNotification(duration time.Duration) error {
+func (e *EmailNotifier) SendConnectionRestoredNotification(ctx context.Context, duration time.Duration) error {
 	subject := "[orgmdns] Conexión a internet restaurada"
 	
 	// Formatear duración de forma legible
@@ -134,21 +272,42 @@ Este es un mensaje automático, por favor no respondas.
 orgmdns
 `, durationStr, time.Now().Format("2006-01-02 15:04:05 MST"))
 
-	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s", e.from, e.to, subject, body)
+	if err := e.send(ctx, subject, body); err != nil {
+		return fmt.Errorf("error enviando correo de restauración: %w", err)
+	}
 
-	auth := smtp.PlainAuth("", e.from, e.password, e.smtpHost)
+	return nil
+}
 
-	addr := fmt.Sprintf("%s:%s", e.smtpHost, e.smtpPort)
-	err := smtp.SendMail(addr, auth, e.from, []string{e.to}, []byte(message))
-	if err != nil {
-		return fmt.Errorf("error enviando correo de restauración: %w", err)
+// SendShutdownNotification envía un correo cuando el verificador DNS se detiene
+func (e *EmailNotifier) SendShutdownNotification(ctx context.Context, uptime time.Duration) error {
+	subject := "[orgmdns] Verificador DNS detenido"
+	body := fmt.Sprintf(`Hola,
+
+El verificador DNS se ha detenido de forma ordenada.
+
+Detalles:
+- Tiempo en ejecución: %s
+- Fecha/hora de apagado: %s
+
+Los registros DNS no se monitorean hasta que el servicio vuelva a iniciarse.
+
+Este es un mensaje automático, por favor no respondas.
+
+--
+orgmdns
+`, uptime.Round(time.Second), time.Now().Format("2006-01-02 15:04:05 MST"))
+
+	if err := e.send(ctx, subject, body); err != nil {
+		return fmt.Errorf("error enviando correo de apagado: %w", err)
 	}
 
 	return nil
 }
 
-// SendErrorNotification envía un correo notificando un error (opcional, para uso futuro)
-func (e *EmailNotifier) SendErrorNotification(errorMsg string) error {
+// SendErrorNotification envía un correo notificando un error que requiere atención
+// (credenciales rechazadas, registro desactivado, etc.)
+func (e *EmailNotifier) SendErrorNotification(ctx context.Context, errorMsg string) error {
 	subject := "[orgmdns] Error en la aplicación"
 	body := fmt.Sprintf(`Hola,
 
@@ -162,15 +321,90 @@ Fecha/hora: %s
 orgmdns
 `, errorMsg, time.Now().Format("2006-01-02 15:04:05 MST"))
 
+	if err := e.send(ctx, subject, body); err != nil {
+		return fmt.Errorf("error enviando correo de error: %w", err)
+	}
+
+	return nil
+}
+
+// formatList formatea una lista como viñetas, una por línea
+func formatList(items []string) string {
+	list := ""
+	for i, item := range items {
+		list += fmt.Sprintf("- %s", item)
+		if i < len(items)-1 {
+			list += "\n"
+		}
+	}
+	return list
+}
+
+// send envía un correo por SMTP respetando la cancelación y el deadline del contexto.
+// Usa STARTTLS y autenticación PLAIN cuando el servidor los ofrece (como smtp.SendMail).
+func (e *EmailNotifier) send(ctx context.Context, subject, body string) error {
 	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s", e.from, e.to, subject, body)
 
-	auth := smtp.PlainAuth("", e.from, e.password, e.smtpHost)
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sendTimeout)
+		defer cancel()
+	}
 
-	addr := fmt.Sprintf("%s:%s", e.smtpHost, e.smtpPort)
-	err := smtp.SendMail(addr, auth, e.from, []string{e.to}, []byte(message))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(e.smtpHost, e.smtpPort))
 	if err != nil {
-		return fmt.Errorf("error enviando correo de error: %w", err)
+		return err
 	}
 
-	return nil
+	// Cortar la conversación SMTP si el contexto se cancela o vence
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, e.smtpHost)
+	if err != nil {
+		conn.Close()
+		return contextError(ctx, err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: e.smtpHost}); err != nil {
+			return contextError(ctx, err)
+		}
+	}
+	if ok, _ := c.Extension("AUTH"); ok {
+		if err := c.Auth(smtp.PlainAuth("", e.from, e.password, e.smtpHost)); err != nil {
+			return contextError(ctx, err)
+		}
+	}
+
+	if err := c.Mail(e.from); err != nil {
+		return contextError(ctx, err)
+	}
+	if err := c.Rcpt(e.to); err != nil {
+		return contextError(ctx, err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return contextError(ctx, err)
+	}
+	if _, err := w.Write([]byte(message)); err != nil {
+		return contextError(ctx, err)
+	}
+	if err := w.Close(); err != nil {
+		return contextError(ctx, err)
+	}
+	return contextError(ctx, c.Quit())
+}
+
+// contextError prioriza el error del contexto cuando la conexión se cortó por cancelación
+func contextError(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("%w: %w", ctx.Err(), err)
+	}
+	return err
 }
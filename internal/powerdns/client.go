@@ -0,0 +1,123 @@
+// Package powerdns implementa un cliente de la API HTTP de PowerDNS Authoritative
+// (/api/v1/servers/{server}/zones/{zone}) con semántica de RRsets.
+package powerdns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/osmargm1202/orgmdns/internal/httputil"
+)
+
+type Client struct {
+	baseURL  string // URL de la API, por ejemplo http://pdns:8081
+	serverID string // normalmente "localhost"
+	api      httputil.JSONClient
+}
+
+// Zone es una zona de PowerDNS. ID y Name son nombres canónicos con punto final.
+type Zone struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Kind   string  `json:"kind"`
+	RRsets []RRset `json:"rrsets"`
+}
+
+// RRset es el conjunto de registros de un nombre y tipo
+type RRset struct {
+	Name     string    `json:"name"`
+	Type     string    `json:"type"`
+	TTL      int       `json:"ttl"`
+	Records  []RR      `json:"records"`
+	Comments []Comment `json:"comments"`
+}
+
+// RRsetChange es un cambio de RRset en el PATCH de una zona.
+// Comments nil deja los comentarios como están; una lista vacía los borra.
+type RRsetChange struct {
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	TTL        int        `json:"ttl,omitempty"`
+	ChangeType string     `json:"changetype"` // REPLACE o DELETE
+	Records    []RR       `json:"records,omitempty"`
+	Comments   *[]Comment `json:"comments,omitempty"`
+}
+
+// RR es un registro individual de un RRset
+type RR struct {
+	Content  string `json:"content"`
+	Disabled bool   `json:"disabled"`
+}
+
+// Comment es un comentario de un RRset
+type Comment struct {
+	Content string `json:"content"`
+	Account string `json:"account"`
+}
+
+// rrsetsPatch es el cuerpo del PATCH de una zona
+type rrsetsPatch struct {
+	RRsets []RRsetChange `json:"rrsets"`
+}
+
+// NewClient crea un cliente para la API de PowerDNS en baseURL (sin /api/v1)
+func NewClient(baseURL, apiKey, serverID string) *Client {
+	return &Client{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		serverID: serverID,
+		api: httputil.JSONClient{
+			HTTPClient: &http.Client{},
+			Auth:       func(req *http.Request) { req.Header.Set("X-API-Key", apiKey) },
+			NewError:   newAPIError,
+		},
+	}
+}
+
+// ListZones obtiene las zonas con el nombre exacto indicado (GET /zones?zone=)
+func (c *Client) ListZones(ctx context.Context, name string) ([]Zone, error) {
+	query := url.Values{}
+	query.Set("zone", canonical(name))
+	reqURL := fmt.Sprintf("%s?%s", c.serverURL("/zones"), query.Encode())
+
+	var zones []Zone
+	if err := c.api.Do(ctx, "GET", reqURL, nil, &zones); err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
+
+// GetZone obtiene la zona con todos sus RRsets (GET /zones/{zone})
+func (c *Client) GetZone(ctx context.Context, zoneID string) (*Zone, error) {
+	var zone Zone
+	if err := c.api.Do(ctx, "GET", c.zoneURL(zoneID), nil, &zone); err != nil {
+		return nil, err
+	}
+	return &zone, nil
+}
+
+// PatchRRsets reemplaza o borra RRsets de la zona (PATCH /zones/{zone})
+func (c *Client) PatchRRsets(ctx context.Context, zoneID string, rrsets []RRsetChange) error {
+	return c.api.Do(ctx, "PATCH", c.zoneURL(zoneID), rrsetsPatch{RRsets: rrsets}, nil)
+}
+
+// serverURL construye la URL de un recurso del servidor configurado
+func (c *Client) serverURL(path string) string {
+	return fmt.Sprintf("%s/api/v1/servers/%s%s", c.baseURL, url.PathEscape(c.serverID), path)
+}
+
+// zoneURL construye la URL de una zona
+func (c *Client) zoneURL(zoneID string) string {
+	return c.serverURL("/zones/" + url.PathEscape(canonical(zoneID)))
+}
+
+// canonical retorna el nombre en minúsculas con punto final, como lo usa PowerDNS
+func canonical(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	return name
+}
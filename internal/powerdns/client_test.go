@@ -0,0 +1,194 @@
+package powerdns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// newTestProvider levanta la API de prueba con handler y crea el proveedor
+func newTestProvider(t *testing.T, handler http.HandlerFunc) *Provider {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-API-Key"); got != "pdns-key" {
+			t.Errorf("X-API-Key = %q", got)
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return NewProvider(NewClient(server.URL+"/", "pdns-key", "localhost"), nil, 300)
+}
+
+func TestListRecords(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/servers/localhost/zones":
+			if got := r.URL.Query().Get("zone"); got != "example.com." {
+				fmt.Fprint(w, `[]`)
+				return
+			}
+			fmt.Fprint(w, `[{"id":"example.com.","name":"example.com.","kind":"Native"}]`)
+		case "/api/v1/servers/localhost/zones/example.com.":
+			fmt.Fprint(w, `{"id":"example.com.","name":"example.com.","rrsets":[
+				{"name":"home.example.com.","type":"A","ttl":300,
+				 "records":[{"content":"203.0.113.1","disabled":false},{"content":"203.0.113.5","disabled":true}],
+				 "comments":[{"content":"router","account":"orgmdns"}]},
+				{"name":"home.example.com.","type":"AAAA","ttl":300,"records":[{"content":"2001:db8::1","disabled":false}],"comments":[]},
+				{"name":"Other.Example.com.","type":"A","ttl":60,"records":[{"content":"203.0.113.2","disabled":false}],"comments":[]}
+			]}`)
+		default:
+			t.Errorf("petición inesperada: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	zone, err := p.ResolveZone(context.Background(), "home.example.com")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if zone.ID != "example.com." || zone.Name != "example.com" {
+		t.Fatalf("zona = %+v", *zone)
+	}
+
+	tests := []struct {
+		name   string
+		filter provider.ListFilter
+		want   []provider.Record
+	}{
+		{
+			name:   "nombre y tipo",
+			filter: provider.ListFilter{Name: "home.example.com", Type: "A"},
+			want:   []provider.Record{{Type: "A", Name: "home.example.com", Content: "203.0.113.1", TTL: 300, Comment: "router"}},
+		},
+		{
+			name:   "nombre sin distinguir mayúsculas",
+			filter: provider.ListFilter{Name: "other.example.com."},
+			want:   []provider.Record{{Type: "A", Name: "Other.Example.com", Content: "203.0.113.2", TTL: 60}},
+		},
+		{
+			name:   "solo tipo",
+			filter: provider.ListFilter{Type: "AAAA"},
+			want:   []provider.Record{{Type: "AAAA", Name: "home.example.com", Content: "2001:db8::1", TTL: 300}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.ListRecords(context.Background(), *zone, tt.filter)
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("registros = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateUpdateAndDeleteRecord(t *testing.T) {
+	var bodies []string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/v1/servers/localhost/zones/example.com." {
+			t.Errorf("petición = %s %s", r.Method, r.URL.Path)
+		}
+		raw, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, strings.TrimSpace(string(raw)))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	zone := provider.Zone{ID: "example.com.", Name: "example.com"}
+	ctx := context.Background()
+
+	created, err := p.CreateRecord(ctx, zone, provider.Record{Type: "A", Name: "Home.example.com", Content: "203.0.113.1", TTL: 1, Comment: "router"})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if created.Name != "home.example.com" || created.TTL != 300 {
+		t.Errorf("registro creado = %+v", *created)
+	}
+
+	// Sin comentario en la actualización se conservan los existentes
+	if _, err := p.UpdateRecord(ctx, zone, *created, provider.RecordUpdate{Content: "203.0.113.9"}); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	// Un comentario vacío los borra
+	empty := ""
+	if _, err := p.UpdateRecord(ctx, zone, *created, provider.RecordUpdate{TTL: 120, Comment: &empty}); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if err := p.DeleteRecord(ctx, zone, *created); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	want := []string{
+		`{"rrsets":[{"name":"home.example.com.","type":"A","ttl":300,"changetype":"REPLACE","records":[{"content":"203.0.113.1","disabled":false}],"comments":[{"content":"router","account":"orgmdns"}]}]}`,
+		`{"rrsets":[{"name":"home.example.com.","type":"A","ttl":300,"changetype":"REPLACE","records":[{"content":"203.0.113.9","disabled":false}]}]}`,
+		`{"rrsets":[{"name":"home.example.com.","type":"A","ttl":120,"changetype":"REPLACE","records":[{"content":"203.0.113.1","disabled":false}],"comments":[]}]}`,
+		`{"rrsets":[{"name":"home.example.com.","type":"A","changetype":"DELETE"}]}`,
+	}
+	if len(bodies) != len(want) {
+		t.Fatalf("peticiones = %q, want %q", bodies, want)
+	}
+	for i := range want {
+		if bodies[i] != want[i] {
+			t.Errorf("PATCH %d = %s, want %s", i, bodies[i], want[i])
+		}
+	}
+}
+
+func TestAPIErrorMapping(t *testing.T) {
+	tests := []struct {
+		statusCode  int
+		body        string
+		wantIs      error
+		wantMessage string
+	}{
+		{statusCode: http.StatusUnauthorized, body: "Unauthorized", wantIs: provider.ErrAuth, wantMessage: "Unauthorized"},
+		{statusCode: http.StatusNotFound, body: `{"error":"Could not find domain 'example.com.'"}`, wantIs: provider.ErrNotFound, wantMessage: "Could not find domain 'example.com.'"},
+		{statusCode: http.StatusUnprocessableEntity, body: `{"error":"RRset home.example.com. IN A: Conflicts with pre-existing RRset"}`, wantIs: provider.ErrValidation, wantMessage: "RRset home.example.com. IN A: Conflicts with pre-existing RRset"},
+		{statusCode: http.StatusTooManyRequests, body: "", wantIs: provider.ErrRateLimited},
+		{statusCode: http.StatusInternalServerError, body: `{"error":"Backend error"}`, wantIs: provider.ErrUnavailable, wantMessage: "Backend error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				fmt.Fprint(w, tt.body)
+			})
+
+			err := p.client.PatchRRsets(context.Background(), "example.com", []RRsetChange{{Name: "home.example.com.", Type: "A", ChangeType: "DELETE"}})
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want *APIError", err)
+			}
+			if apiErr.Message != tt.wantMessage || apiErr.Method != http.MethodPatch || apiErr.Path != "/api/v1/servers/localhost/zones/example.com." {
+				t.Errorf("APIError = %+v", *apiErr)
+			}
+			if !errors.Is(err, tt.wantIs) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.wantIs)
+			}
+			for _, other := range []error{provider.ErrAuth, provider.ErrNotFound, provider.ErrValidation, provider.ErrRateLimited, provider.ErrUnavailable} {
+				if other != tt.wantIs && errors.Is(err, other) {
+					t.Errorf("errors.Is(%v, %v) = true", err, other)
+				}
+			}
+		})
+	}
+}
+
+func TestGetRecordNotFound(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"example.com.","name":"example.com.","rrsets":[]}`)
+	})
+
+	_, err := p.GetRecord(context.Background(), provider.Zone{ID: "example.com.", Name: "example.com"}, "home.example.com", "A")
+	if !errors.Is(err, provider.ErrNotFound) {
+		t.Errorf("error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,46 @@
+package powerdns
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/osmargm1202/orgmdns/internal/httputil"
+)
+
+// APIError es un error retornado por la API de PowerDNS (status no exitoso)
+type APIError struct {
+	StatusCode int
+	Message    string // campo "error" de la respuesta o cuerpo crudo (recortado)
+	Method     string
+	Path       string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("error de API de PowerDNS: %s %s status %d", e.Method, e.Path, e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Is clasifica el status HTTP en las categorías de internal/provider
+func (e *APIError) Is(target error) bool {
+	return httputil.StatusIs(e.StatusCode, target)
+}
+
+// newAPIError construye un APIError a partir de la respuesta HTTP
+func newAPIError(resp *http.Response, body []byte) error {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	apiErr.Method, apiErr.Path = httputil.RequestPath(resp)
+
+	var errResp struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error != "" {
+		apiErr.Message = errResp.Error
+	} else {
+		apiErr.Message = httputil.Truncate(string(body), 512)
+	}
+	return apiErr
+}
@@ -0,0 +1,185 @@
+package powerdns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// ProviderName es el nombre del proveedor en DNS_PROVIDER y ZONE_PROVIDERS
+const ProviderName = "powerdns"
+
+// Cuenta con la que se firman los comentarios creados por orgmdns
+const commentAccount = "orgmdns"
+
+var _ provider.DNSProvider = (*Provider)(nil)
+
+// Provider adapta la API de PowerDNS a provider.DNSProvider. Cada registro
+// gestionado es un RRset de un solo valor que se reemplaza completo (REPLACE).
+type Provider struct {
+	client     *Client
+	defaultTTL int // TTL usado cuando el registro pide TTL automático (1)
+
+	mu    sync.Mutex
+	zones map[string]bool // zonas configuradas o descubiertas (nombre canónico)
+}
+
+// NewProvider crea el proveedor de PowerDNS. zones son las zonas configuradas en
+// ZONE_PROVIDERS; las demás se descubren con /zones?zone=.
+func NewProvider(client *Client, zones []string, defaultTTL int) *Provider {
+	p := &Provider{
+		client:     client,
+		defaultTTL: defaultTTL,
+		zones:      make(map[string]bool),
+	}
+	for _, zone := range zones {
+		p.zones[canonical(zone)] = true
+	}
+	return p
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// Capabilities: TTL y comentario (por RRset); PowerDNS no tiene proxy ni etiquetas
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{TTL: true, Comment: true, ListZone: true, DefaultTTL: p.defaultTTL}
+}
+
+// ResolveZone busca la zona por sufijo más largo entre las conocidas y, si no
+// hay, la descubre con la API
+func (p *Provider) ResolveZone(ctx context.Context, recordName string) (*provider.Zone, error) {
+	labels := strings.Split(strings.TrimSuffix(canonical(recordName), "."), ".")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := 0; i < len(labels)-1; i++ {
+		if candidate := canonical(strings.Join(labels[i:], ".")); p.zones[candidate] {
+			return newZone(candidate), nil
+		}
+	}
+
+	for i := 0; i < len(labels)-1; i++ {
+		candidate := canonical(strings.Join(labels[i:], "."))
+		zones, err := p.client.ListZones(ctx, candidate)
+		if err != nil {
+			return nil, fmt.Errorf("error buscando zona %s: %w", candidate, err)
+		}
+		if len(zones) > 0 {
+			p.zones[canonical(zones[0].Name)] = true
+			return newZone(zones[0].Name), nil
+		}
+	}
+
+	return nil, fmt.Errorf("no se encontró zona de PowerDNS para %s: %w", recordName, provider.ErrNotFound)
+}
+
+// ListRecords obtiene la zona y retorna los registros habilitados que cumplen el filtro
+func (p *Provider) ListRecords(ctx context.Context, zone provider.Zone, filter provider.ListFilter) ([]provider.Record, error) {
+	pdnsZone, err := p.client.GetZone(ctx, zone.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []provider.Record
+	for _, rrset := range pdnsZone.RRsets {
+		if filter.Name != "" && canonical(rrset.Name) != canonical(filter.Name) {
+			continue
+		}
+		if filter.Type != "" && rrset.Type != filter.Type {
+			continue
+		}
+		for _, rr := range rrset.Records {
+			if rr.Disabled {
+				continue
+			}
+			record := provider.Record{
+				Type:    rrset.Type,
+				Name:    strings.TrimSuffix(rrset.Name, "."),
+				Content: rr.Content,
+				TTL:     rrset.TTL,
+			}
+			if len(rrset.Comments) > 0 {
+				record.Comment = rrset.Comments[0].Content
+			}
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (p *Provider) GetRecord(ctx context.Context, zone provider.Zone, name, recordType string) (*provider.Record, error) {
+	records, err := p.ListRecords(ctx, zone, provider.ListFilter{Name: name, Type: recordType})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("registro %s %s: %w", recordType, name, provider.ErrNotFound)
+	}
+	return &records[0], nil
+}
+
+// CreateRecord crea el RRset con el registro indicado
+func (p *Provider) CreateRecord(ctx context.Context, zone provider.Zone, record provider.Record) (*provider.Record, error) {
+	var comment *string
+	if record.Comment != "" {
+		comment = &record.Comment
+	}
+	return p.replace(ctx, zone, record, comment)
+}
+
+// UpdateRecord reemplaza el RRset por el registro actualizado
+func (p *Provider) UpdateRecord(ctx context.Context, zone provider.Zone, current provider.Record, update provider.RecordUpdate) (*provider.Record, error) {
+	return p.replace(ctx, zone, provider.ApplyUpdate(current, update), update.Comment)
+}
+
+// DeleteRecord borra el RRset del registro
+func (p *Provider) DeleteRecord(ctx context.Context, zone provider.Zone, record provider.Record) error {
+	return p.client.PatchRRsets(ctx, zone.ID, []RRsetChange{{
+		Name:       canonical(record.Name),
+		Type:       record.Type,
+		ChangeType: "DELETE",
+	}})
+}
+
+// replace envía un REPLACE del RRset con un único valor. comment nil deja los
+// comentarios existentes; "" los borra.
+func (p *Provider) replace(ctx context.Context, zone provider.Zone, record provider.Record, comment *string) (*provider.Record, error) {
+	if record.TTL <= 1 {
+		record.TTL = p.defaultTTL
+	}
+
+	change := RRsetChange{
+		Name:       canonical(record.Name),
+		Type:       record.Type,
+		TTL:        record.TTL,
+		ChangeType: "REPLACE",
+		Records:    []RR{{Content: record.Content}},
+	}
+	if comment != nil {
+		comments := []Comment{}
+		if *comment != "" {
+			comments = append(comments, Comment{Content: *comment, Account: commentAccount})
+		}
+		change.Comments = &comments
+	}
+
+	if err := p.client.PatchRRsets(ctx, zone.ID, []RRsetChange{change}); err != nil {
+		return nil, err
+	}
+
+	record.Name = strings.TrimSuffix(change.Name, ".")
+	record.Proxied = false
+	record.Tags = nil
+	return &record, nil
+}
+
+// newZone construye la zona común; el ID es el nombre canónico usado en la URL
+func newZone(name string) *provider.Zone {
+	return &provider.Zone{ID: canonical(name), Name: strings.TrimSuffix(canonical(name), ".")}
+}
@@ -0,0 +1,48 @@
+package provider
+
+import "errors"
+
+// Categorías de error comunes a todos los proveedores. Cada proveedor hace que
+// sus errores cumplan errors.Is con la categoría correspondiente para que el
+// Runner decida qué hacer sin conocer el proveedor.
+var (
+	ErrAuth        = errors.New("error de autenticación o permisos")
+	ErrNotFound    = errors.New("recurso no encontrado")
+	ErrRateLimited = errors.New("rate limit excedido")
+	ErrValidation  = errors.New("petición inválida")
+	ErrUnavailable = errors.New("proveedor no disponible temporalmente")
+	ErrConflict    = errors.New("el registro cambió en el proveedor")
+
+	// ErrBatchUnsupported indica que el proveedor no puede aplicar el lote
+	// (Batcher); los cambios se aplican uno por uno
+	ErrBatchUnsupported = errors.New("el proveedor no admite cambios por lote")
+
+	// ErrUnverified indica que la verificación inicial (Preflighter) no pudo
+	// determinar un permiso; no es un error de configuración
+	ErrUnverified = errors.New("no se pudo verificar el permiso")
+)
+
+// IsAuthError indica si el error es de autenticación o permisos
+func IsAuthError(err error) bool { return errors.Is(err, ErrAuth) }
+
+// IsNotFound indica si el recurso (zona o registro) no existe
+func IsNotFound(err error) bool { return errors.Is(err, ErrNotFound) }
+
+// IsRateLimited indica si el proveedor rechazó la petición por rate limit
+func IsRateLimited(err error) bool { return errors.Is(err, ErrRateLimited) }
+
+// IsValidation indica si el proveedor rechazó la petición por datos inválidos
+func IsValidation(err error) bool { return errors.Is(err, ErrValidation) }
+
+// IsUnavailable indica si el proveedor no está disponible (circuit breaker, caída)
+func IsUnavailable(err error) bool { return errors.Is(err, ErrUnavailable) }
+
+// IsConflict indica si el registro cambió entre la lectura y la modificación
+// (por ejemplo un prerrequisito de RFC 2136 no cumplido)
+func IsConflict(err error) bool { return errors.Is(err, ErrConflict) }
+
+// IsBatchUnsupported indica si el proveedor rechazó un lote por no admitirlo
+func IsBatchUnsupported(err error) bool { return errors.Is(err, ErrBatchUnsupported) }
+
+// IsUnverified indica si la verificación inicial no pudo determinar un permiso
+func IsUnverified(err error) bool { return errors.Is(err, ErrUnverified) }
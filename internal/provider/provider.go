@@ -0,0 +1,163 @@
+// Package provider define la interfaz común de los proveedores DNS. El Runner
+// reconcilia los registros contra esta interfaz, de modo que agregar un proveedor
+// nuevo no requiere tocar la lógica de reconciliación.
+package provider
+
+import "context"
+
+// Zone es una zona DNS de un proveedor. ID es el identificador propio del
+// proveedor (puede coincidir con el nombre si el proveedor no usa IDs).
+type Zone struct {
+	ID   string
+	Name string
+}
+
+// Record es un registro DNS tal como lo reporta el proveedor
+type Record struct {
+	ID      string // identificador del proveedor, vacío si no usa IDs
+	Type    string
+	Name    string
+	Content string
+	TTL     int // segundos, 1 = automático, 0 = hereda el TTL de la zona
+	Proxied bool
+	Comment string
+	Tags    []string
+}
+
+// RecordUpdate contiene los campos a modificar de un registro.
+// Solo se aplican los campos no vacíos (nil para punteros).
+type RecordUpdate struct {
+	Content string
+	TTL     int
+	Proxied *bool
+	Comment *string
+	Tags    *[]string
+}
+
+// IsEmpty indica si la actualización no modifica ningún campo
+func (u RecordUpdate) IsEmpty() bool {
+	return u.Content == "" && u.TTL == 0 && u.Proxied == nil && u.Comment == nil && u.Tags == nil
+}
+
+// ListFilter filtra el listado de registros. Los campos vacíos no filtran.
+type ListFilter struct {
+	Name string // nombre exacto (FQDN)
+	Type string // A, AAAA, etc.
+}
+
+// Capabilities indica qué ajustes de registro soporta el proveedor. Los ajustes
+// no soportados se ignoran al comparar y al crear registros.
+type Capabilities struct {
+	TTL     bool
+	Proxied bool
+	Comment bool
+	Tags    bool
+
+	// DefaultTTL es el TTL que escribe el proveedor cuando se pide TTL automático (1),
+	// en los proveedores que no lo soportan; 0 = el proveedor guarda TTL automático
+	DefaultTTL int
+
+	// ListZone indica si ListRecords puede listar la zona completa (filtro sin
+	// nombre). Si no, el estado de la zona se obtiene con GetRecord por registro.
+	ListZone bool
+}
+
+// DNSProvider es un proveedor DNS capaz de listar, crear, actualizar y borrar
+// registros de sus zonas
+type DNSProvider interface {
+	// Name retorna el nombre del proveedor usado en la configuración (DNS_PROVIDER, ZONE_PROVIDERS)
+	Name() string
+	// Capabilities retorna los ajustes de registro soportados
+	Capabilities() Capabilities
+	// ResolveZone retorna la zona que contiene el nombre de registro indicado
+	ResolveZone(ctx context.Context, recordName string) (*Zone, error)
+	// ListRecords retorna los registros de la zona que cumplen el filtro
+	ListRecords(ctx context.Context, zone Zone, filter ListFilter) ([]Record, error)
+	// GetRecord retorna el registro con el nombre y tipo indicados (ErrNotFound si no existe)
+	GetRecord(ctx context.Context, zone Zone, name, recordType string) (*Record, error)
+	// CreateRecord crea el registro y retorna el registro creado
+	CreateRecord(ctx context.Context, zone Zone, record Record) (*Record, error)
+	// UpdateRecord aplica update sobre el registro actual y retorna el registro resultante
+	UpdateRecord(ctx context.Context, zone Zone, current Record, update RecordUpdate) (*Record, error)
+	// DeleteRecord borra el registro
+	DeleteRecord(ctx context.Context, zone Zone, record Record) error
+}
+
+// CycleAware lo implementan los proveedores con estado por ciclo (por ejemplo
+// presupuesto de reintentos o circuit breaker)
+type CycleAware interface {
+	// BeginCycle se llama al inicio de cada ciclo. Un error indica que el
+	// proveedor no está disponible y sus zonas se omiten en este ciclo.
+	BeginCycle() error
+	// EndCycle se llama al final de cada ciclo. Un error indica que el proveedor
+	// quedó degradado (solo se registra en el log).
+	EndCycle() error
+}
+
+// ApplyUpdate retorna una copia de current con los campos de update aplicados.
+// Útil para proveedores cuya API no retorna el registro modificado.
+func ApplyUpdate(current Record, update RecordUpdate) Record {
+	record := current
+	if update.Content != "" {
+		record.Content = update.Content
+	}
+	if update.TTL != 0 {
+		record.TTL = update.TTL
+	}
+	if update.Proxied != nil {
+		record.Proxied = *update.Proxied
+	}
+	if update.Comment != nil {
+		record.Comment = *update.Comment
+	}
+	if update.Tags != nil {
+		record.Tags = append([]string(nil), (*update.Tags)...)
+	}
+	return record
+}
+
+// Preflighter lo implementan los proveedores que pueden verificar credenciales y
+// permisos antes del primer ciclo (recordNames son los registros que gestionarán).
+// Si un permiso no se puede determinar, el error envuelve ErrUnverified.
+type Preflighter interface {
+	Preflight(ctx context.Context, recordNames []string) error
+}
+
+// IPList es una lista de IPs fuera del DNS (por ejemplo, una lista de IPs usada en
+// reglas de firewall) que debe contener la IP pública actual
+type IPList interface {
+	Name() string
+	// SyncIP deja ip (familia de recordType) en la lista en lugar de la anterior.
+	// Retorna nil si la lista ya estaba al día.
+	SyncIP(ctx context.Context, recordType, ip string) (*IPListChange, error)
+}
+
+// IPListPlanner lo implementan las listas de IPs que pueden calcular el cambio de
+// SyncIP sin aplicarlo (modo dry-run)
+type IPListPlanner interface {
+	PlanIP(ctx context.Context, recordType, ip string) (*IPListChange, error)
+}
+
+// IPListChange describe un cambio aplicado a una lista de IPs
+type IPListChange struct {
+	List       string   `json:"list"`
+	RecordType string   `json:"type"`              // familia: A (IPv4) o AAAA (IPv6)
+	Added      string   `json:"added,omitempty"`   // elemento agregado ("" si ya estaba)
+	Removed    []string `json:"removed,omitempty"` // elementos anteriores eliminados
+}
+
+// RecordChange es la actualización de un registro existente dentro de un lote
+type RecordChange struct {
+	Current Record
+	Update  RecordUpdate
+}
+
+// Batcher lo implementan los proveedores que pueden aplicar varios cambios de una
+// zona en una sola petición atómica: o se aplican todos o ninguno. Los registros
+// resultantes se retornan en el mismo orden que creates y updates; si la respuesta
+// del proveedor está incompleta, pueden ser menos (sin error). Si el proveedor
+// no permite lotes en la zona (plan, permisos), retorna ErrBatchUnsupported y el
+// Runner aplica los cambios uno por uno.
+type Batcher interface {
+	ApplyBatch(ctx context.Context, zone Zone, creates []Record, updates []RecordChange) (created, updated []Record, err error)
+}
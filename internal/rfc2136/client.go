@@ -0,0 +1,125 @@
+// Package rfc2136 implementa actualizaciones dinámicas DNS (RFC 2136) firmadas con
+// TSIG (RFC 8945), para servidores como BIND o Knot.
+package rfc2136
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Tiempo máximo de cada consulta o actualización si el contexto no trae deadline
+const exchangeTimeout = 10 * time.Second
+
+// Margen de reloj aceptado en las firmas TSIG (segundos)
+const tsigFudge = 300
+
+// Algoritmos TSIG soportados (nombre de configuración -> nombre DNS)
+var tsigAlgorithms = map[string]string{
+	"hmac-sha256": dns.HmacSHA256,
+	"hmac-sha512": dns.HmacSHA512,
+}
+
+// Client envía consultas y actualizaciones a un servidor DNS autoritativo
+type Client struct {
+	server    string // host:puerto
+	network   string // udp o tcp
+	keyName   string // nombre de la clave TSIG (FQDN), vacío = sin firma
+	secret    string // secreto TSIG en base64
+	algorithm string // nombre DNS del algoritmo TSIG
+}
+
+// NewClient crea un cliente para el servidor indicado (host o host:puerto, puerto 53
+// por defecto). network es "udp" o "tcp". Si keyName está vacío no se firma con TSIG.
+func NewClient(server, network, keyName, secret, algorithm string) (*Client, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	c := &Client{
+		server:  server,
+		network: network,
+	}
+	if keyName != "" {
+		alg, ok := tsigAlgorithms[strings.ToLower(algorithm)]
+		if !ok {
+			return nil, fmt.Errorf("algoritmo TSIG no soportado: %s (válidos: hmac-sha256, hmac-sha512)", algorithm)
+		}
+		if _, err := base64.StdEncoding.DecodeString(secret); err != nil {
+			return nil, fmt.Errorf("secreto TSIG inválido (se espera base64): %w", err)
+		}
+		c.keyName = dns.Fqdn(keyName)
+		c.secret = secret
+		c.algorithm = alg
+	}
+	return c, nil
+}
+
+// Query consulta el RRset del nombre y tipo indicados directamente al servidor
+func (c *Client) Query(ctx context.Context, name string, rrType uint16) (*dns.Msg, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(name), rrType)
+	m.RecursionDesired = false
+	return c.exchange(ctx, m)
+}
+
+// Update envía el mensaje UPDATE y verifica el código de respuesta
+func (c *Client) Update(ctx context.Context, m *dns.Msg) error {
+	_, err := c.exchange(ctx, m)
+	return err
+}
+
+// exchange firma el mensaje (si hay clave TSIG), lo envía y clasifica el resultado.
+// Si una respuesta UDP llega truncada se repite por TCP.
+func (c *Client) exchange(ctx context.Context, m *dns.Msg) (*dns.Msg, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, exchangeTimeout)
+		defer cancel()
+	}
+
+	client := &dns.Client{Net: c.network}
+	if c.keyName != "" {
+		client.TsigSecret = map[string]string{c.keyName: c.secret}
+	}
+
+	resp, err := c.send(ctx, client, m)
+	if err == nil && resp.Truncated && c.network != "tcp" {
+		client.Net = "tcp"
+		resp, err = c.send(ctx, client, m)
+	}
+	if err != nil {
+		return nil, classifyExchangeError(err)
+	}
+
+	if resp.Rcode != dns.RcodeSuccess {
+		return resp, newRcodeError(m, resp)
+	}
+	return resp, nil
+}
+
+// send firma el mensaje con una marca de tiempo nueva y lo envía
+func (c *Client) send(ctx context.Context, client *dns.Client, m *dns.Msg) (*dns.Msg, error) {
+	if c.keyName != "" {
+		removeTsig(m)
+		m.SetTsig(c.keyName, c.algorithm, tsigFudge, time.Now().Unix())
+	}
+	resp, _, err := client.ExchangeContext(ctx, m, c.server)
+	return resp, err
+}
+
+// removeTsig quita la firma TSIG de un mensaje para volver a firmarlo
+func removeTsig(m *dns.Msg) {
+	extra := m.Extra[:0]
+	for _, rr := range m.Extra {
+		if _, ok := rr.(*dns.TSIG); !ok {
+			extra = append(extra, rr)
+		}
+	}
+	m.Extra = extra
+}
@@ -0,0 +1,71 @@
+package rfc2136
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/miekg/dns"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// ResponseError es una respuesta del servidor con código distinto de NOERROR
+type ResponseError struct {
+	Opcode int // dns.OpcodeQuery o dns.OpcodeUpdate
+	Name   string
+	Rcode  int
+	TSIG   uint16 // código de error TSIG de la respuesta (BADSIG, BADKEY, BADTIME), 0 si no hay
+}
+
+func (e *ResponseError) Error() string {
+	op := "consulta"
+	if e.Opcode == dns.OpcodeUpdate {
+		op = "actualización"
+	}
+	msg := fmt.Sprintf("error de RFC 2136: %s de %s rechazada con %s", op, e.Name, dns.RcodeToString[e.Rcode])
+	if e.TSIG != 0 {
+		msg += fmt.Sprintf(" (TSIG %s)", dns.RcodeToString[int(e.TSIG)])
+	}
+	return msg
+}
+
+// Is clasifica el código de respuesta en las categorías de internal/provider
+func (e *ResponseError) Is(target error) bool {
+	switch target {
+	case provider.ErrAuth:
+		return e.Rcode == dns.RcodeNotAuth || e.Rcode == dns.RcodeRefused || e.TSIG != 0
+	case provider.ErrNotFound:
+		// NXDOMAIN en una consulta: el nombre no existe
+		return e.Opcode == dns.OpcodeQuery && e.Rcode == dns.RcodeNameError
+	case provider.ErrConflict:
+		// Prerrequisitos no cumplidos: el RRset cambió desde la lectura
+		return e.Opcode == dns.OpcodeUpdate && (e.Rcode == dns.RcodeYXDomain || e.Rcode == dns.RcodeYXRrset ||
+			e.Rcode == dns.RcodeNXRrset || e.Rcode == dns.RcodeNameError)
+	case provider.ErrValidation:
+		return e.Rcode == dns.RcodeFormatError || e.Rcode == dns.RcodeNotImplemented || e.Rcode == dns.RcodeNotZone
+	case provider.ErrUnavailable:
+		return e.Rcode == dns.RcodeServerFailure
+	}
+	return false
+}
+
+// newRcodeError construye un ResponseError a partir de la respuesta del servidor
+func newRcodeError(req, resp *dns.Msg) *ResponseError {
+	respErr := &ResponseError{Opcode: req.Opcode, Rcode: resp.Rcode}
+	if len(req.Question) > 0 {
+		respErr.Name = req.Question[0].Name
+	}
+	if tsig := resp.IsTsig(); tsig != nil {
+		respErr.TSIG = tsig.Error
+	}
+	return respErr
+}
+
+// classifyExchangeError marca como error de autenticación los fallos al verificar
+// la firma TSIG de la respuesta
+func classifyExchangeError(err error) error {
+	if errors.Is(err, dns.ErrSig) || errors.Is(err, dns.ErrSecret) || errors.Is(err, dns.ErrKeyAlg) || errors.Is(err, dns.ErrTime) {
+		return fmt.Errorf("error verificando TSIG de la respuesta: %w: %w", provider.ErrAuth, err)
+	}
+	return fmt.Errorf("error comunicando con el servidor DNS: %w", err)
+}
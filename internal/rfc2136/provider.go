@@ -0,0 +1,254 @@
+package rfc2136
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/miekg/dns"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// ProviderName es el nombre del proveedor en DNS_PROVIDER y ZONE_PROVIDERS
+const ProviderName = "rfc2136"
+
+var _ provider.DNSProvider = (*Provider)(nil)
+
+// Provider adapta las actualizaciones dinámicas RFC 2136 a provider.DNSProvider.
+// El servidor no tiene IDs de registro: los registros se identifican por nombre,
+// tipo y contenido.
+type Provider struct {
+	client        *Client
+	defaultTTL    int  // TTL usado cuando el registro pide TTL automático (1)
+	prerequisites bool // exigir prerrequisitos en cada actualización
+
+	mu    sync.Mutex
+	zones map[string]bool // zonas configuradas o descubiertas por SOA
+}
+
+// NewProvider crea el proveedor RFC 2136. zones son las zonas configuradas en
+// ZONE_PROVIDERS; las demás se descubren consultando el SOA al servidor.
+// Con prerequisites cada cambio exige que el RRset siga como se leyó (RFC 2136 §2.4),
+// de modo que no se pisan cambios hechos por otro cliente.
+func NewProvider(client *Client, zones []string, defaultTTL int, prerequisites bool) *Provider {
+	p := &Provider{
+		client:        client,
+		defaultTTL:    defaultTTL,
+		prerequisites: prerequisites,
+		zones:         make(map[string]bool),
+	}
+	for _, zone := range zones {
+		p.zones[dns.Fqdn(strings.ToLower(zone))] = true
+	}
+	return p
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// Capabilities: solo TTL; el servidor no permite listar la zona sin AXFR
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{TTL: true, DefaultTTL: p.defaultTTL}
+}
+
+// ResolveZone busca la zona configurada por sufijo más largo o, si no hay, pregunta
+// el SOA del nombre al servidor
+func (p *Provider) ResolveZone(ctx context.Context, recordName string) (*provider.Zone, error) {
+	name := dns.Fqdn(strings.ToLower(strings.TrimSpace(recordName)))
+
+	p.mu.Lock()
+	for _, i := range dns.Split(name) {
+		if candidate := name[i:]; p.zones[candidate] {
+			p.mu.Unlock()
+			return newZone(candidate), nil
+		}
+	}
+	p.mu.Unlock()
+
+	resp, err := p.client.Query(ctx, name, dns.TypeSOA)
+	if err != nil && !provider.IsNotFound(err) {
+		return nil, fmt.Errorf("error buscando zona de %s: %w", recordName, err)
+	}
+	if resp != nil {
+		for _, rr := range append(resp.Answer, resp.Ns...) {
+			if soa, ok := rr.(*dns.SOA); ok {
+				zone := strings.ToLower(soa.Hdr.Name)
+				p.mu.Lock()
+				p.zones[zone] = true
+				p.mu.Unlock()
+				return newZone(zone), nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("el servidor %s no es autoritativo para %s: %w", p.client.server, recordName, provider.ErrNotFound)
+}
+
+// ListRecords consulta el RRset del nombre del filtro (el filtro de nombre es obligatorio)
+func (p *Provider) ListRecords(ctx context.Context, zone provider.Zone, filter provider.ListFilter) ([]provider.Record, error) {
+	if filter.Name == "" || filter.Type == "" {
+		return nil, fmt.Errorf("RFC 2136 requiere nombre y tipo para listar registros: %w", provider.ErrValidation)
+	}
+	rrType, err := rrTypeOf(filter.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Query(ctx, filter.Name, rrType)
+	if provider.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []provider.Record
+	for _, rr := range resp.Answer {
+		// Ignorar CNAME y registros de otros nombres que el servidor agregue a la respuesta
+		if !strings.EqualFold(rr.Header().Name, dns.Fqdn(filter.Name)) || rr.Header().Rrtype != rrType {
+			continue
+		}
+		if record, ok := toProviderRecord(rr); ok {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (p *Provider) GetRecord(ctx context.Context, zone provider.Zone, name, recordType string) (*provider.Record, error) {
+	records, err := p.ListRecords(ctx, zone, provider.ListFilter{Name: name, Type: recordType})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("registro %s %s: %w", recordType, name, provider.ErrNotFound)
+	}
+	return &records[0], nil
+}
+
+// CreateRecord agrega el registro; con prerrequisitos exige que el RRset no exista
+func (p *Provider) CreateRecord(ctx context.Context, zone provider.Zone, record provider.Record) (*provider.Record, error) {
+	rr, err := p.newRR(record.Name, record.Type, record.Content, record.TTL)
+	if err != nil {
+		return nil, err
+	}
+
+	m := new(dns.Msg)
+	m.SetUpdate(dns.Fqdn(zone.Name))
+	if p.prerequisites {
+		m.RRsetNotUsed([]dns.RR{rr})
+	}
+	m.Insert([]dns.RR{rr})
+
+	if err := p.client.Update(ctx, m); err != nil {
+		return nil, err
+	}
+
+	created, _ := toProviderRecord(rr)
+	return &created, nil
+}
+
+// UpdateRecord reemplaza el RRset por el registro actualizado; con prerrequisitos
+// exige que el registro actual siga existiendo con el mismo contenido
+func (p *Provider) UpdateRecord(ctx context.Context, zone provider.Zone, current provider.Record, update provider.RecordUpdate) (*provider.Record, error) {
+	updated := provider.ApplyUpdate(current, update)
+	rr, err := p.newRR(updated.Name, updated.Type, updated.Content, updated.TTL)
+	if err != nil {
+		return nil, err
+	}
+
+	m := new(dns.Msg)
+	m.SetUpdate(dns.Fqdn(zone.Name))
+	if p.prerequisites {
+		old, err := p.newRR(current.Name, current.Type, current.Content, current.TTL)
+		if err != nil {
+			return nil, err
+		}
+		m.Used([]dns.RR{old})
+	}
+	m.RemoveRRset([]dns.RR{rr})
+	m.Insert([]dns.RR{rr})
+
+	if err := p.client.Update(ctx, m); err != nil {
+		return nil, err
+	}
+
+	result, _ := toProviderRecord(rr)
+	return &result, nil
+}
+
+// DeleteRecord borra el registro (nombre, tipo y contenido)
+func (p *Provider) DeleteRecord(ctx context.Context, zone provider.Zone, record provider.Record) error {
+	rr, err := p.newRR(record.Name, record.Type, record.Content, record.TTL)
+	if err != nil {
+		return err
+	}
+
+	m := new(dns.Msg)
+	m.SetUpdate(dns.Fqdn(zone.Name))
+	if p.prerequisites {
+		m.Used([]dns.RR{rr})
+	}
+	m.Remove([]dns.RR{rr})
+
+	return p.client.Update(ctx, m)
+}
+
+// newRR construye un registro A o AAAA; TTL 1 (automático) usa el TTL por defecto
+func (p *Provider) newRR(name, recordType, content string, ttl int) (dns.RR, error) {
+	if ttl <= 1 {
+		ttl = p.defaultTTL
+	}
+	hdr := dns.RR_Header{Name: dns.Fqdn(name), Class: dns.ClassINET, Ttl: uint32(ttl)}
+
+	ip := net.ParseIP(content)
+	switch {
+	case recordType == "A" && ip != nil && ip.To4() != nil:
+		hdr.Rrtype = dns.TypeA
+		return &dns.A{Hdr: hdr, A: ip.To4()}, nil
+	case recordType == "AAAA" && ip != nil && ip.To4() == nil:
+		hdr.Rrtype = dns.TypeAAAA
+		return &dns.AAAA{Hdr: hdr, AAAA: ip}, nil
+	}
+	return nil, fmt.Errorf("contenido %q inválido para un registro %s: %w", content, recordType, provider.ErrValidation)
+}
+
+// rrTypeOf convierte el tipo de registro soportado a su código DNS
+func rrTypeOf(recordType string) (uint16, error) {
+	switch recordType {
+	case "A":
+		return dns.TypeA, nil
+	case "AAAA":
+		return dns.TypeAAAA, nil
+	}
+	return 0, fmt.Errorf("tipo de registro no soportado: %s: %w", recordType, provider.ErrValidation)
+}
+
+// toProviderRecord convierte un registro A o AAAA al tipo común
+func toProviderRecord(rr dns.RR) (provider.Record, bool) {
+	record := provider.Record{
+		Name: strings.TrimSuffix(rr.Header().Name, "."),
+		TTL:  int(rr.Header().Ttl),
+	}
+	switch v := rr.(type) {
+	case *dns.A:
+		record.Type = "A"
+		record.Content = v.A.String()
+	case *dns.AAAA:
+		record.Type = "AAAA"
+		record.Content = v.AAAA.String()
+	default:
+		return provider.Record{}, false
+	}
+	return record, true
+}
+
+// newZone construye la zona común; el ID es el nombre de la zona
+func newZone(fqdn string) *provider.Zone {
+	name := strings.TrimSuffix(fqdn, ".")
+	return &provider.Zone{ID: name, Name: name}
+}
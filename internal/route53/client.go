@@ -0,0 +1,285 @@
+// Package route53 implementa un cliente mínimo de la API REST de AWS Route 53
+// (2013-04-01) firmado con SigV4, sin depender del SDK de AWS.
+package route53
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/osmargm1202/orgmdns/internal/httputil"
+)
+
+const (
+	apiVersion = "2013-04-01"
+	xmlns      = "https://route53.amazonaws.com/doc/2013-04-01/"
+
+	// Tamaño de página usado al listar record sets
+	listMaxItems = 300
+)
+
+// Estados de un cambio (GetChange)
+const (
+	ChangePending = "PENDING"
+	ChangeInSync  = "INSYNC"
+)
+
+type Client struct {
+	creds      Credentials
+	endpoint   string // por defecto https://route53.amazonaws.com
+	region     string // región de firma, us-east-1 para Route 53
+	httpClient *http.Client
+}
+
+// HostedZone es una zona alojada. ID sin el prefijo /hostedzone/.
+type HostedZone struct {
+	ID     string `xml:"Id"`
+	Name   string `xml:"Name"`
+	Config struct {
+		PrivateZone bool `xml:"PrivateZone"`
+	} `xml:"Config"`
+}
+
+// ResourceRecordSet es el conjunto de registros de un nombre y tipo
+type ResourceRecordSet struct {
+	Name            string           `xml:"Name"`
+	Type            string           `xml:"Type"`
+	TTL             int              `xml:"TTL,omitempty"`
+	ResourceRecords []ResourceRecord `xml:"ResourceRecords>ResourceRecord"`
+	AliasTarget     *struct {
+		DNSName string `xml:"DNSName"`
+	} `xml:"AliasTarget,omitempty"`
+}
+
+// ResourceRecord es un valor de un record set
+type ResourceRecord struct {
+	Value string `xml:"Value"`
+}
+
+// Change es un cambio de ChangeResourceRecordSets (UPSERT, CREATE o DELETE)
+type Change struct {
+	Action            string            `xml:"Action"`
+	ResourceRecordSet ResourceRecordSet `xml:"ResourceRecordSet"`
+}
+
+// ChangeInfo describe el estado de un cambio enviado
+type ChangeInfo struct {
+	ID     string `xml:"Id"`
+	Status string `xml:"Status"`
+}
+
+type listHostedZonesByNameResponse struct {
+	HostedZones []HostedZone `xml:"HostedZones>HostedZone"`
+}
+
+type listResourceRecordSetsResponse struct {
+	ResourceRecordSets []ResourceRecordSet `xml:"ResourceRecordSets>ResourceRecordSet"`
+	IsTruncated        bool                `xml:"IsTruncated"`
+	NextRecordName     string              `xml:"NextRecordName"`
+	NextRecordType     string              `xml:"NextRecordType"`
+}
+
+type changeResourceRecordSetsRequest struct {
+	XMLName xml.Name `xml:"ChangeResourceRecordSetsRequest"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Comment string   `xml:"ChangeBatch>Comment,omitempty"`
+	Changes []Change `xml:"ChangeBatch>Changes>Change"`
+}
+
+type changeInfoResponse struct {
+	ChangeInfo ChangeInfo `xml:"ChangeInfo"`
+}
+
+// NewClient crea un cliente de Route 53. endpoint vacío usa el endpoint público
+// de AWS; se puede apuntar a un stub local para pruebas.
+func NewClient(creds Credentials, endpoint, region string) *Client {
+	if endpoint == "" {
+		endpoint = "https://route53.amazonaws.com"
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &Client{
+		creds:      creds,
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		region:     region,
+		httpClient: &http.Client{},
+	}
+}
+
+// Endpoint retorna la URL de la API usada por el cliente
+func (c *Client) Endpoint() string {
+	return c.endpoint
+}
+
+// Region retorna la región con la que se firman las peticiones
+func (c *Client) Region() string {
+	return c.region
+}
+
+// ListHostedZonesByName obtiene las zonas con el nombre exacto indicado
+func (c *Client) ListHostedZonesByName(ctx context.Context, name string) ([]HostedZone, error) {
+	query := url.Values{}
+	query.Set("dnsname", name)
+	query.Set("maxitems", "10")
+
+	var resp listHostedZonesByNameResponse
+	if err := c.do(ctx, "GET", "/hostedzonesbyname", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	// dnsname es el punto de inicio del listado: filtrar las coincidencias exactas
+	var zones []HostedZone
+	for _, zone := range resp.HostedZones {
+		if canonical(zone.Name) == canonical(name) {
+			zone.ID = trimIDPrefix(zone.ID)
+			zones = append(zones, zone)
+		}
+	}
+	return zones, nil
+}
+
+// ListResourceRecordSets obtiene los record sets de la zona. name y recordType
+// (opcionales) indican desde dónde empieza el listado (en orden de Route 53). Con
+// limit > 0 se retorna una sola página de hasta limit record sets; con 0 se
+// recorren todas las páginas.
+func (c *Client) ListResourceRecordSets(ctx context.Context, zoneID, name, recordType string, limit int) ([]ResourceRecordSet, error) {
+	var sets []ResourceRecordSet
+
+	maxItems := listMaxItems
+	if limit > 0 {
+		maxItems = limit
+	}
+	query := url.Values{}
+	query.Set("maxitems", strconv.Itoa(maxItems))
+	if name != "" {
+		query.Set("name", name)
+		if recordType != "" {
+			query.Set("type", recordType)
+		}
+	}
+
+	for {
+		var resp listResourceRecordSetsResponse
+		if err := c.do(ctx, "GET", "/hostedzone/"+zoneID+"/rrset", query, nil, &resp); err != nil {
+			return nil, err
+		}
+		sets = append(sets, resp.ResourceRecordSets...)
+
+		if limit > 0 || !resp.IsTruncated || resp.NextRecordName == "" {
+			break
+		}
+		query.Set("name", resp.NextRecordName)
+		query.Set("type", resp.NextRecordType)
+	}
+
+	return sets, nil
+}
+
+// ChangeResourceRecordSets envía un lote de cambios y retorna su estado
+func (c *Client) ChangeResourceRecordSets(ctx context.Context, zoneID, comment string, changes []Change) (*ChangeInfo, error) {
+	body := changeResourceRecordSetsRequest{Xmlns: xmlns, Comment: comment, Changes: changes}
+
+	var resp changeInfoResponse
+	if err := c.do(ctx, "POST", "/hostedzone/"+zoneID+"/rrset/", nil, body, &resp); err != nil {
+		return nil, err
+	}
+	resp.ChangeInfo.ID = trimIDPrefix(resp.ChangeInfo.ID)
+	return &resp.ChangeInfo, nil
+}
+
+// GetChange obtiene el estado de un cambio (PENDING o INSYNC)
+func (c *Client) GetChange(ctx context.Context, changeID string) (*ChangeInfo, error) {
+	var resp changeInfoResponse
+	if err := c.do(ctx, "GET", "/change/"+changeID, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	resp.ChangeInfo.ID = trimIDPrefix(resp.ChangeInfo.ID)
+	return &resp.ChangeInfo, nil
+}
+
+// WaitForChange consulta el cambio cada interval hasta que esté INSYNC o se cancele ctx
+func (c *Client) WaitForChange(ctx context.Context, changeID string, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		info, err := c.GetChange(ctx, changeID)
+		if err != nil {
+			return err
+		}
+		if info.Status == ChangeInSync {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("cambio %s sigue %s: %w", changeID, info.Status, ctx.Err())
+		}
+	}
+}
+
+// do ejecuta una petición firmada a la API y decodifica la respuesta XML en out
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, payload interface{}, out interface{}) error {
+	var body []byte
+	if payload != nil {
+		var err error
+		body, err = xml.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("error serializando request: %w", err)
+		}
+		body = append([]byte(xml.Header), body...)
+	}
+
+	reqURL := c.endpoint + "/" + apiVersion + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creando request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/xml")
+	}
+	signV4(req, body, c.creds, c.region, "route53", time.Now())
+
+	resp, respBody, err := httputil.Do(c.httpClient, req)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError(resp, respBody)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := xml.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("error parseando respuesta: %w", err)
+	}
+	return nil
+}
+
+// trimIDPrefix quita los prefijos /hostedzone/ y /change/ de los IDs
+func trimIDPrefix(id string) string {
+	id = strings.TrimPrefix(id, "/hostedzone/")
+	return strings.TrimPrefix(id, "/change/")
+}
+
+// canonical retorna el nombre en minúsculas con punto final, como lo usa Route 53
+func canonical(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	return name
+}
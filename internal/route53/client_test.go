@@ -0,0 +1,213 @@
+package route53
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// newTestProvider levanta la API de prueba con handler y crea el proveedor
+func newTestProvider(t *testing.T, zones map[string]string, handler http.HandlerFunc) *Provider {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(auth, "/us-east-1/route53/aws4_request") {
+			t.Errorf("Authorization = %q", auth)
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, server.URL+"/", "")
+	return NewProvider(client, zones, 300, 0)
+}
+
+func TestResolveZone(t *testing.T) {
+	p := newTestProvider(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2013-04-01/hostedzonesbyname" {
+			t.Errorf("petición inesperada: %s %s", r.Method, r.URL.Path)
+		}
+		// El listado empieza en dnsname: incluye zonas posteriores que no coinciden
+		switch r.URL.Query().Get("dnsname") {
+		case "example.com.":
+			fmt.Fprint(w, `<ListHostedZonesByNameResponse><HostedZones>
+				<HostedZone><Id>/hostedzone/ZPRIVATE</Id><Name>example.com.</Name><Config><PrivateZone>true</PrivateZone></Config></HostedZone>
+				<HostedZone><Id>/hostedzone/ZPUBLIC</Id><Name>example.com.</Name><Config><PrivateZone>false</PrivateZone></Config></HostedZone>
+			</HostedZones></ListHostedZonesByNameResponse>`)
+		default:
+			fmt.Fprint(w, `<ListHostedZonesByNameResponse><HostedZones>
+				<HostedZone><Id>/hostedzone/ZOTHER</Id><Name>example.net.</Name></HostedZone>
+			</HostedZones></ListHostedZonesByNameResponse>`)
+		}
+	})
+
+	zone, err := p.ResolveZone(context.Background(), "home.example.com")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if zone.ID != "ZPUBLIC" || zone.Name != "example.com" {
+		t.Errorf("zona = %+v, want la zona pública ZPUBLIC", *zone)
+	}
+
+	if _, err := p.ResolveZone(context.Background(), "home.example.org"); !errors.Is(err, provider.ErrNotFound) {
+		t.Errorf("zona inexistente: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestListRecordsPagination(t *testing.T) {
+	var queries []string
+	p := newTestProvider(t, map[string]string{"example.com": "/hostedzone/Z1"}, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/2013-04-01/hostedzone/Z1/rrset" {
+			t.Errorf("petición = %s %s", r.Method, r.URL.Path)
+		}
+		query := r.URL.Query()
+		queries = append(queries, query.Get("name")+"/"+query.Get("type"))
+		switch query.Get("name") {
+		case "":
+			fmt.Fprint(w, `<ListResourceRecordSetsResponse><ResourceRecordSets>
+				<ResourceRecordSet><Name>example.com.</Name><Type>A</Type><AliasTarget><DNSName>lb.example.net.</DNSName></AliasTarget></ResourceRecordSet>
+				<ResourceRecordSet><Name>\052.example.com.</Name><Type>A</Type><TTL>60</TTL><ResourceRecords><ResourceRecord><Value>203.0.113.1</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+			</ResourceRecordSets><IsTruncated>true</IsTruncated><NextRecordName>home.example.com.</NextRecordName><NextRecordType>A</NextRecordType></ListResourceRecordSetsResponse>`)
+		case "home.example.com.":
+			fmt.Fprint(w, `<ListResourceRecordSetsResponse><ResourceRecordSets>
+				<ResourceRecordSet><Name>home.example.com.</Name><Type>A</Type><TTL>300</TTL><ResourceRecords><ResourceRecord><Value>203.0.113.2</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+				<ResourceRecordSet><Name>home.example.com.</Name><Type>AAAA</Type><TTL>300</TTL><ResourceRecords><ResourceRecord><Value>2001:db8::1</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+			</ResourceRecordSets><IsTruncated>false</IsTruncated></ListResourceRecordSetsResponse>`)
+		}
+	})
+
+	zone, err := p.ResolveZone(context.Background(), "home.example.com")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	records, err := p.ListRecords(context.Background(), *zone, provider.ListFilter{Type: "A"})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	if fmt.Sprint(queries) != "[/ home.example.com./A]" {
+		t.Errorf("páginas = %v", queries)
+	}
+	// Los alias se omiten y \052 se convierte en *
+	want := []provider.Record{
+		{Type: "A", Name: "*.example.com", Content: "203.0.113.1", TTL: 60},
+		{Type: "A", Name: "home.example.com", Content: "203.0.113.2", TTL: 300},
+	}
+	if fmt.Sprint(records) != fmt.Sprint(want) {
+		t.Errorf("registros = %+v, want %+v", records, want)
+	}
+}
+
+func TestCreateAndUpdateRecord(t *testing.T) {
+	var changes []changeResourceRecordSetsRequest
+	var polls int
+	p := newTestProvider(t, map[string]string{"example.com": "Z1"}, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/2013-04-01/hostedzone/Z1/rrset/":
+			if got := r.Header.Get("Content-Type"); got != "application/xml" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			raw, _ := io.ReadAll(r.Body)
+			var change changeResourceRecordSetsRequest
+			if err := xml.Unmarshal(raw, &change); err != nil {
+				t.Errorf("cuerpo inválido: %v", err)
+			}
+			changes = append(changes, change)
+			fmt.Fprint(w, `<ChangeResourceRecordSetsResponse><ChangeInfo><Id>/change/C1</Id><Status>PENDING</Status></ChangeInfo></ChangeResourceRecordSetsResponse>`)
+		case r.Method == http.MethodGet && r.URL.Path == "/2013-04-01/change/C1":
+			polls++
+			fmt.Fprint(w, `<GetChangeResponse><ChangeInfo><Id>/change/C1</Id><Status>INSYNC</Status></ChangeInfo></GetChangeResponse>`)
+		default:
+			t.Errorf("petición inesperada: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	p.waitTimeout = time.Second
+	zone := provider.Zone{ID: "Z1", Name: "example.com"}
+
+	created, err := p.CreateRecord(context.Background(), zone, provider.Record{Type: "A", Name: "home.example.com", Content: "203.0.113.1", TTL: 1, Comment: "router"})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if created.TTL != 300 || created.Comment != "" {
+		t.Errorf("registro creado = %+v", *created)
+	}
+	if _, err := p.UpdateRecord(context.Background(), zone, *created, provider.RecordUpdate{Content: "203.0.113.9", TTL: 120}); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	if len(changes) != 2 || polls != 2 {
+		t.Fatalf("cambios = %d, consultas de GetChange = %d, want 2 y 2", len(changes), polls)
+	}
+	for i, want := range []struct {
+		value string
+		ttl   int
+	}{{"203.0.113.1", 300}, {"203.0.113.9", 120}} {
+		change := changes[i]
+		if change.Comment != changeComment || len(change.Changes) != 1 {
+			t.Fatalf("lote %d = %+v", i, change)
+		}
+		set := change.Changes[0].ResourceRecordSet
+		if change.Changes[0].Action != "UPSERT" || set.Name != "home.example.com." || set.Type != "A" || set.TTL != want.ttl ||
+			len(set.ResourceRecords) != 1 || set.ResourceRecords[0].Value != want.value {
+			t.Errorf("cambio %d = %+v", i, change.Changes[0])
+		}
+	}
+}
+
+func TestAPIErrorMapping(t *testing.T) {
+	errorBody := func(code string) string {
+		return fmt.Sprintf(`<ErrorResponse><Error><Type>Sender</Type><Code>%s</Code><Message>detalle</Message></Error><RequestId>req-1</RequestId></ErrorResponse>`, code)
+	}
+
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantIs     error
+	}{
+		{name: "AccessDenied", statusCode: http.StatusForbidden, body: errorBody("AccessDenied"), wantIs: provider.ErrAuth},
+		{name: "SignatureDoesNotMatch", statusCode: http.StatusForbidden, body: errorBody("SignatureDoesNotMatch"), wantIs: provider.ErrAuth},
+		{name: "NoSuchHostedZone", statusCode: http.StatusNotFound, body: errorBody("NoSuchHostedZone"), wantIs: provider.ErrNotFound},
+		{name: "InvalidChangeBatch", statusCode: http.StatusBadRequest, body: errorBody("InvalidChangeBatch"), wantIs: provider.ErrValidation},
+		{name: "Throttling", statusCode: http.StatusBadRequest, body: errorBody("Throttling"), wantIs: provider.ErrRateLimited},
+		{name: "PriorRequestNotComplete", statusCode: http.StatusBadRequest, body: errorBody("PriorRequestNotComplete"), wantIs: provider.ErrRateLimited},
+		{name: "5xx sin XML", statusCode: http.StatusServiceUnavailable, body: "Service Unavailable", wantIs: provider.ErrUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProvider(t, nil, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				fmt.Fprint(w, tt.body)
+			})
+
+			_, err := p.client.ChangeResourceRecordSets(context.Background(), "Z1", "", []Change{{Action: "UPSERT"}})
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want *APIError", err)
+			}
+			if apiErr.Method != http.MethodPost || apiErr.Path != "/2013-04-01/hostedzone/Z1/rrset/" {
+				t.Errorf("APIError = %+v", *apiErr)
+			}
+			if strings.HasPrefix(tt.body, "<") && (apiErr.Code != tt.name || apiErr.RequestID != "req-1") {
+				t.Errorf("Code/RequestID = %q/%q", apiErr.Code, apiErr.RequestID)
+			}
+			if !errors.Is(err, tt.wantIs) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.wantIs)
+			}
+			for _, other := range []error{provider.ErrAuth, provider.ErrNotFound, provider.ErrValidation, provider.ErrRateLimited, provider.ErrUnavailable} {
+				if other != tt.wantIs && errors.Is(err, other) {
+					t.Errorf("errors.Is(%v, %v) = true", err, other)
+				}
+			}
+		})
+	}
+}
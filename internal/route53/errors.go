@@ -0,0 +1,98 @@
+package route53
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+
+	"github.com/osmargm1202/orgmdns/internal/httputil"
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// Códigos de error de Route 53 usados para clasificar respuestas
+var (
+	authErrorCodes       = []string{"AccessDenied", "InvalidClientTokenId", "SignatureDoesNotMatch", "IncompleteSignature", "ExpiredToken", "UnrecognizedClientException"}
+	notFoundErrorCodes   = []string{"NoSuchHostedZone", "NoSuchChange"}
+	rateLimitErrorCodes  = []string{"Throttling", "ThrottlingException", "PriorRequestNotComplete"}
+	validationErrorCodes = []string{"InvalidChangeBatch", "InvalidInput", "InvalidDomainName"}
+)
+
+// APIError es un error retornado por la API de Route 53
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	RequestID  string
+	Method     string
+	Path       string
+}
+
+// errorResponse es el cuerpo XML de una respuesta de error
+type errorResponse struct {
+	Error struct {
+		Type    string `xml:"Type"`
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Error"`
+	RequestID string `xml:"RequestId"`
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("error de API de Route 53: %s %s status %d", e.Method, e.Path, e.StatusCode)
+	if e.Code != "" {
+		msg += fmt.Sprintf(" [%s: %s]", e.Code, e.Message)
+	} else if e.Message != "" {
+		msg += ", body: " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += " (request id " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// Is clasifica el error en las categorías de internal/provider
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case provider.ErrAuth:
+		return e.StatusCode == http.StatusForbidden || hasCode(e.Code, authErrorCodes)
+	case provider.ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || hasCode(e.Code, notFoundErrorCodes)
+	case provider.ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests || hasCode(e.Code, rateLimitErrorCodes)
+	case provider.ErrValidation:
+		return hasCode(e.Code, validationErrorCodes)
+	case provider.ErrUnavailable:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+func hasCode(code string, codes []string) bool {
+	for _, c := range codes {
+		if code == c {
+			return true
+		}
+	}
+	return false
+}
+
+// newAPIError construye un APIError a partir de la respuesta HTTP
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Amzn-RequestId"),
+	}
+	apiErr.Method, apiErr.Path = httputil.RequestPath(resp)
+
+	var errResp errorResponse
+	if err := xml.Unmarshal(body, &errResp); err == nil && errResp.Error.Code != "" {
+		apiErr.Code = errResp.Error.Code
+		apiErr.Message = errResp.Error.Message
+		if errResp.RequestID != "" {
+			apiErr.RequestID = errResp.RequestID
+		}
+	} else {
+		apiErr.Message = httputil.Truncate(string(body), 512)
+	}
+	return apiErr
+}
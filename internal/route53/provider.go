@@ -0,0 +1,206 @@
+package route53
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// ProviderName es el nombre del proveedor en DNS_PROVIDER y ZONE_PROVIDERS
+const ProviderName = "route53"
+
+// Intervalo entre consultas de GetChange al esperar INSYNC
+const waitInterval = 5 * time.Second
+
+// Comentario de los lotes de cambios enviados por orgmdns
+const changeComment = "orgmdns"
+
+var _ provider.DNSProvider = (*Provider)(nil)
+
+// Provider adapta la API de Route 53 a provider.DNSProvider. Cada registro gestionado
+// es un record set de un solo valor que se escribe con UPSERT.
+type Provider struct {
+	client      *Client
+	defaultTTL  int           // TTL usado cuando el registro pide TTL automático (1)
+	waitTimeout time.Duration // espera máxima a INSYNC tras cada cambio, 0 = no esperar
+
+	mu    sync.Mutex
+	zones map[string]string // nombre canónico -> ID de hosted zone (explícitas y descubiertas)
+}
+
+// NewProvider crea el proveedor de Route 53. zones mapea nombre de zona a ID de
+// hosted zone; las zonas sin ID se descubren con ListHostedZonesByName. Con
+// waitTimeout > 0 cada cambio espera hasta INSYNC (o hasta agotar la espera, en
+// cuyo caso el cambio queda aplicado y se continúa).
+func NewProvider(client *Client, zones map[string]string, defaultTTL int, waitTimeout time.Duration) *Provider {
+	p := &Provider{
+		client:      client,
+		defaultTTL:  defaultTTL,
+		waitTimeout: waitTimeout,
+		zones:       make(map[string]string),
+	}
+	for name, id := range zones {
+		p.zones[canonical(name)] = trimIDPrefix(id)
+	}
+	return p
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// Capabilities: solo TTL; Route 53 no tiene proxy, comentarios ni etiquetas por registro
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{TTL: true, ListZone: true, DefaultTTL: p.defaultTTL}
+}
+
+// ResolveZone busca la hosted zone por sufijo más largo entre las conocidas y, si no
+// hay, la descubre por nombre (prefiriendo zonas públicas)
+func (p *Provider) ResolveZone(ctx context.Context, recordName string) (*provider.Zone, error) {
+	labels := strings.Split(strings.TrimSuffix(canonical(recordName), "."), ".")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := 0; i < len(labels)-1; i++ {
+		candidate := canonical(strings.Join(labels[i:], "."))
+		if id, ok := p.zones[candidate]; ok && id != "" {
+			return newZone(id, candidate), nil
+		}
+	}
+
+	for i := 0; i < len(labels)-1; i++ {
+		candidate := canonical(strings.Join(labels[i:], "."))
+		zones, err := p.client.ListHostedZonesByName(ctx, candidate)
+		if err != nil {
+			return nil, fmt.Errorf("error buscando zona %s: %w", candidate, err)
+		}
+		if len(zones) == 0 {
+			continue
+		}
+
+		zone := zones[0]
+		for _, z := range zones {
+			if !z.Config.PrivateZone {
+				zone = z
+				break
+			}
+		}
+		p.zones[candidate] = zone.ID
+		return newZone(zone.ID, candidate), nil
+	}
+
+	return nil, fmt.Errorf("no se encontró hosted zone de Route 53 para %s: %w", recordName, provider.ErrNotFound)
+}
+
+// ListRecords retorna los registros A/AAAA (sin alias) de la zona que cumplen el filtro
+func (p *Provider) ListRecords(ctx context.Context, zone provider.Zone, filter provider.ListFilter) ([]provider.Record, error) {
+	var sets []ResourceRecordSet
+	var err error
+	if filter.Name != "" {
+		// El listado empieza en el nombre indicado: basta con la primera página
+		sets, err = p.client.ListResourceRecordSets(ctx, zone.ID, canonical(filter.Name), filter.Type, 10)
+	} else {
+		sets, err = p.client.ListResourceRecordSets(ctx, zone.ID, "", "", 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []provider.Record
+	for _, set := range sets {
+		name := unescapeName(set.Name)
+		if filter.Name != "" && canonical(name) != canonical(filter.Name) {
+			continue
+		}
+		if filter.Type != "" && set.Type != filter.Type {
+			continue
+		}
+		if set.AliasTarget != nil {
+			continue
+		}
+		for _, rr := range set.ResourceRecords {
+			records = append(records, provider.Record{
+				Type:    set.Type,
+				Name:    strings.TrimSuffix(name, "."),
+				Content: rr.Value,
+				TTL:     set.TTL,
+			})
+		}
+	}
+	return records, nil
+}
+
+func (p *Provider) GetRecord(ctx context.Context, zone provider.Zone, name, recordType string) (*provider.Record, error) {
+	records, err := p.ListRecords(ctx, zone, provider.ListFilter{Name: name, Type: recordType})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("registro %s %s: %w", recordType, name, provider.ErrNotFound)
+	}
+	return &records[0], nil
+}
+
+// CreateRecord escribe el record set con UPSERT
+func (p *Provider) CreateRecord(ctx context.Context, zone provider.Zone, record provider.Record) (*provider.Record, error) {
+	return p.change(ctx, zone, "UPSERT", record)
+}
+
+// UpdateRecord reemplaza el record set con UPSERT
+func (p *Provider) UpdateRecord(ctx context.Context, zone provider.Zone, current provider.Record, update provider.RecordUpdate) (*provider.Record, error) {
+	return p.change(ctx, zone, "UPSERT", provider.ApplyUpdate(current, update))
+}
+
+// DeleteRecord borra el record set (Route 53 exige el TTL y los valores actuales)
+func (p *Provider) DeleteRecord(ctx context.Context, zone provider.Zone, record provider.Record) error {
+	_, err := p.change(ctx, zone, "DELETE", record)
+	return err
+}
+
+// change envía un cambio de un record set de un solo valor y, si está configurado,
+// espera a que el cambio esté INSYNC
+func (p *Provider) change(ctx context.Context, zone provider.Zone, action string, record provider.Record) (*provider.Record, error) {
+	if record.TTL <= 1 {
+		record.TTL = p.defaultTTL
+	}
+
+	info, err := p.client.ChangeResourceRecordSets(ctx, zone.ID, changeComment, []Change{{
+		Action: action,
+		ResourceRecordSet: ResourceRecordSet{
+			Name:            canonical(record.Name),
+			Type:            record.Type,
+			TTL:             record.TTL,
+			ResourceRecords: []ResourceRecord{{Value: record.Content}},
+		},
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	if p.waitTimeout > 0 && info.Status != ChangeInSync {
+		waitCtx, cancel := context.WithTimeout(ctx, p.waitTimeout)
+		defer cancel()
+		// El cambio ya fue aceptado: si no llega a INSYNC a tiempo se continúa igual
+		_ = p.client.WaitForChange(waitCtx, info.ID, waitInterval)
+	}
+
+	record.Proxied = false
+	record.Comment = ""
+	record.Tags = nil
+	return &record, nil
+}
+
+// unescapeName convierte los caracteres escapados por Route 53 (\052 = *)
+func unescapeName(name string) string {
+	return strings.ReplaceAll(name, `\052`, "*")
+}
+
+// newZone construye la zona común a partir del ID y el nombre canónico
+func newZone(id, name string) *provider.Zone {
+	return &provider.Zone{ID: id, Name: strings.TrimSuffix(name, ".")}
+}
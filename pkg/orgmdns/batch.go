@@ -0,0 +1,85 @@
+package orgmdns
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// applyBatch aplica los cambios de una zona en un solo lote atómico si el proveedor
+// implementa Batcher, para que un fallo a mitad de camino no deje unos registros con
+// la IP nueva y otros con la anterior. Retorna applied=true si el lote se aplicó y
+// cont=false si se deben detener las operaciones del proveedor en este ciclo.
+//   - Lote no admitido: se recuerda para el proveedor y los cambios se aplican uno por uno.
+//   - Lote rechazado por datos inválidos, conflicto o registro inexistente: no se aplicó
+//     nada, así que se aplican uno por uno para aislar el registro problemático.
+//   - Resto de errores: se retorna el error y se reintenta en el siguiente ciclo
+//     (el lote pudo aplicarse sin que llegara la respuesta).
+func (u *Updater) applyBatch(ctx context.Context, target *zoneTarget, snapshot zoneSnapshot, ops []*recordOp) (applied, cont bool, err error) {
+	name := target.provider.Name()
+	batcher, ok := target.provider.(provider.Batcher)
+	if !ok || u.batchUnsupported[name] {
+		return false, true, nil
+	}
+	if u.stopping() {
+		u.logger.Info("Apagado solicitado: no se procesan más registros en este ciclo")
+		return false, true, nil
+	}
+
+	var (
+		creates   []provider.Record
+		updates   []provider.RecordChange
+		createOps []*recordOp
+		updateOps []*recordOp
+	)
+	for _, op := range ops {
+		if op.create {
+			creates = append(creates, op.newRecord)
+			createOps = append(createOps, op)
+		} else {
+			updates = append(updates, provider.RecordChange{Current: op.current, Update: op.update})
+			updateOps = append(updateOps, op)
+		}
+	}
+
+	u.logger.Info(fmt.Sprintf("Aplicando %d cambios de la zona %s en %s en un solo lote", len(ops), target.zone.Name, name))
+	created, updated, err := batcher.ApplyBatch(ctx, target.zone, creates, updates)
+	switch {
+	case err == nil:
+	case provider.IsBatchUnsupported(err):
+		u.batchUnsupported[name] = true
+		u.logger.Info(fmt.Sprintf("%s no admite cambios por lote, se aplican uno por uno: %v", name, err))
+		return false, true, nil
+	case provider.IsValidation(err) || provider.IsConflict(err) || provider.IsNotFound(err):
+		u.logger.Info(fmt.Sprintf("Lote de la zona %s rechazado por %s, se aplican los cambios uno por uno: %v", target.zone.Name, name, err))
+		return false, true, nil
+	default:
+		u.logger.Error(fmt.Sprintf("Error aplicando el lote de la zona %s en %s: %v", target.zone.Name, name, err))
+		err = fmt.Errorf("lote de la zona %s en %s: %w", target.zone.Name, name, err)
+		return false, !u.handleAPIError(ctx, name, err), err
+	}
+
+	if len(created) < len(createOps) || len(updated) < len(updateOps) {
+		// El lote se aplicó pero faltan resultados: los cambios sin resultado se
+		// notifican igual y el siguiente ciclo parte de un snapshot nuevo
+		u.logger.Info(fmt.Sprintf("Respuesta incompleta del lote de la zona %s en %s: %d/%d creaciones, %d/%d actualizaciones",
+			target.zone.Name, name, len(created), len(createOps), len(updated), len(updateOps)))
+	}
+
+	for i, op := range updateOps {
+		if i < len(updated) {
+			u.finishRecord(ctx, snapshot, op, updated[i])
+		} else {
+			u.reportRecord(ctx, op, "")
+		}
+	}
+	for i, op := range createOps {
+		if i < len(created) {
+			u.finishRecord(ctx, snapshot, op, created[i])
+		} else {
+			u.reportRecord(ctx, op, "")
+		}
+	}
+	return true, true, nil
+}
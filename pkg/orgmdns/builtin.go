@@ -0,0 +1,125 @@
+package orgmdns
+
+import (
+	"github.com/osmargm1202/orgmdns/internal/cloudflare"
+	"github.com/osmargm1202/orgmdns/internal/ip"
+	"github.com/osmargm1202/orgmdns/internal/notify"
+)
+
+// DefaultIPSource retorna la fuente de IP pública por defecto: STUN y, si falla,
+// servicios HTTP, forzando la familia de cada tipo de registro
+func DefaultIPSource() IPSource {
+	return ip.DefaultChain()
+}
+
+// NamedIPSource es una fuente de IP con nombre (para los logs) que se puede
+// combinar en NewIPSourceChain
+type NamedIPSource = ip.IPSource
+
+// IPSourceChainEntry es una fuente de NewIPSourceChain con su timeout y las familias
+// (tipos de registro) que atiende
+type IPSourceChainEntry = ip.ChainEntry
+
+// NewIPSourceChain crea una fuente que prueba las fuentes en orden, cada una con su
+// timeout, y retorna la primera IP válida de la familia pedida
+func NewIPSourceChain(entries ...IPSourceChainEntry) IPSource {
+	return ip.NewChain(entries...)
+}
+
+// IPConsensusResult es el detalle de una consulta por consenso: los votos de cada
+// fuente y la IP acordada
+type IPConsensusResult = ip.ConsensusResult
+
+// ErrNoConsensus lo envuelve el error de NewIPSourceConsensus cuando ninguna IP
+// alcanza el quorum
+var ErrNoConsensus = ip.ErrNoConsensus
+
+// NewIPSourceConsensus crea una fuente que consulta todas las fuentes en paralelo y
+// solo acepta una IP si al menos quorum coinciden (y ninguna otra IP empata).
+// report (opcional) recibe los resultados en los que las fuentes no coinciden.
+func NewIPSourceConsensus(quorum int, report func(IPConsensusResult), entries ...IPSourceChainEntry) IPSource {
+	return ip.NewConsensus(quorum, report, entries...)
+}
+
+// AddressError es el error de una fuente que respondió una IP que no es pública
+// (privada, loopback, link-local, documentación, bogon o carrier-grade NAT)
+type AddressError = ip.AddressError
+
+// Categorías de AddressError, para usar con errors.Is
+var (
+	ErrNotPublic = ip.ErrNotPublic
+	ErrCGNAT     = ip.ErrCGNAT
+)
+
+// CheckPublicIP retorna un *AddressError si la IP no es alcanzable desde internet
+func CheckPublicIP(address string) error { return ip.CheckPublic(address) }
+
+// NewSTUNSource crea una fuente STUN ("" = stun.l.google.com:19302)
+func NewSTUNSource(server string) NamedIPSource { return ip.NewSTUNSource(server) }
+
+// NewHTTPSource crea una fuente HTTP que lee la IP de la primera línea de la respuesta
+func NewHTTPSource(url string) NamedIPSource { return ip.NewHTTPSource(url) }
+
+// Métodos de NewDNSSource
+const (
+	DNSMethodOpenDNS = ip.DNSMethodOpenDNS // myip.opendns.com A/AAAA
+	DNSMethodGoogle  = ip.DNSMethodGoogle  // o-o.myaddr.l.google.com TXT
+)
+
+// NewDNSSource crea una fuente que pregunta la propia dirección a servidores DNS con
+// el método indicado ("" = DNSMethodOpenDNS); sin resolvers usa los del método
+func NewDNSSource(method string, resolvers ...string) NamedIPSource {
+	return ip.NewDNSSource(method, resolvers...)
+}
+
+// NewInterfaceSource crea una fuente que lee la dirección pública de una interfaz local
+func NewInterfaceSource(iface string) NamedIPSource { return ip.NewInterfaceSource(iface) }
+
+// NewCommandSource crea una fuente que ejecuta un comando de shell que imprime la IP
+func NewCommandSource(command string) NamedIPSource { return ip.NewCommandSource(command) }
+
+// NewUPnPSource crea una fuente que pide la dirección WAN al router por UPnP IGD
+// (location = URL de la descripción del router, "" = descubrirlo con SSDP). Solo IPv4.
+func NewUPnPSource(location string) NamedIPSource { return ip.NewUPnPSource(location) }
+
+// NewNATPMPSource crea una fuente que pide la dirección WAN al gateway por NAT-PMP o
+// PCP ("" = gateway por defecto, solo en Linux). Solo IPv4.
+func NewNATPMPSource(gateway string) NamedIPSource { return ip.NewNATPMPSource(gateway) }
+
+// IPNATStatus compara la IP pública con la dirección WAN del router (ver DoubleNAT)
+type IPNATStatus = ip.NATStatus
+
+// NewDoubleNATCheck envuelve source y, tras cada detección, compara la IP con la
+// dirección WAN del router; report recibe el estado la primera vez y cada vez que
+// cambia. Sin routers usa UPnP IGD y NAT-PMP/PCP al gateway por defecto.
+func NewDoubleNATCheck(source IPSource, report func(IPNATStatus), routers ...IPSourceChainEntry) IPSource {
+	named, ok := source.(NamedIPSource)
+	if !ok {
+		named = unnamedIPSource{source}
+	}
+	if len(routers) == 0 {
+		routers = ip.DefaultRouterEntries(0)
+	}
+	return ip.NewNATCheck(named, report, routers...)
+}
+
+// unnamedIPSource da nombre a una IPSource propia para combinarla con las incluidas
+type unnamedIPSource struct {
+	IPSource
+}
+
+func (unnamedIPSource) Name() string { return "custom" }
+
+// NewEmailNotifier crea un notificador que envía los avisos por correo (SMTP con
+// STARTTLS y autenticación PLAIN cuando el servidor los ofrece)
+func NewEmailNotifier(from, to, password, smtpHost, smtpPort string) Notifier {
+	return notify.NewEmailNotifier(from, to, password, smtpHost, smtpPort)
+}
+
+// NewCloudflareProvider crea el proveedor de Cloudflare con la política de
+// reintentos por defecto. apiKey es un API Token o, junto con email, una API Key
+// legacy. zones mapea nombre de zona a ID; las demás zonas se descubren por nombre.
+func NewCloudflareProvider(apiKey, email string, zones map[string]string) DNSProvider {
+	client := cloudflare.NewClient("", apiKey, email)
+	return cloudflare.NewProvider(client, cloudflare.NewZoneResolver(client, zones, nil))
+}
@@ -0,0 +1,93 @@
+package orgmdns
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// diffSettings compara los ajustes deseados del registro (TTL, proxied, comentario y
+// etiquetas) con el registro actual. Retorna la actualización con solo los campos que
+// difieren y una descripción legible de cada cambio. Los ajustes que el proveedor no
+// soporta se ignoran.
+func diffSettings(desired Record, current provider.Record, caps provider.Capabilities) (provider.RecordUpdate, []string) {
+	var update provider.RecordUpdate
+	var changes []string
+
+	proxied := current.Proxied
+	if caps.Proxied && desired.Proxied != nil && *desired.Proxied != current.Proxied {
+		update.Proxied = desired.Proxied
+		proxied = *desired.Proxied
+		changes = append(changes, fmt.Sprintf("proxied: %t -> %t", current.Proxied, *desired.Proxied))
+	}
+
+	// Los registros con proxy siempre usan TTL automático en Cloudflare. Los proveedores
+	// sin TTL automático guardan su TTL por defecto en lugar de 1: se compara con ese
+	// valor para que el registro converja. Un registro que hereda el TTL de la zona
+	// (0) tiene TTL automático.
+	desiredTTL := desired.TTL
+	if desiredTTL == 1 && caps.DefaultTTL > 0 {
+		desiredTTL = caps.DefaultTTL
+	}
+	currentTTL := current.TTL
+	if currentTTL == 0 {
+		currentTTL = 1
+	}
+	if caps.TTL && desiredTTL != 0 && !proxied && desiredTTL != currentTTL {
+		update.TTL = desired.TTL
+		changes = append(changes, fmt.Sprintf("TTL: %s -> %s", formatTTL(current.TTL), formatTTL(desiredTTL)))
+	}
+
+	if caps.Comment && desired.Comment != nil && *desired.Comment != current.Comment {
+		update.Comment = desired.Comment
+		changes = append(changes, fmt.Sprintf("comentario: %q -> %q", current.Comment, *desired.Comment))
+	}
+
+	if caps.Tags && desired.Tags != nil {
+		// El orden de las etiquetas no importa: se comparan ambos lados ordenados
+		currentTags := append([]string(nil), current.Tags...)
+		sort.Strings(currentTags)
+		desiredTags := append([]string{}, desired.Tags...)
+		sort.Strings(desiredTags)
+		if strings.Join(currentTags, "|") != strings.Join(desiredTags, "|") {
+			update.Tags = &desiredTags
+			changes = append(changes, fmt.Sprintf("etiquetas: [%s] -> [%s]", strings.Join(currentTags, ", "), strings.Join(desiredTags, ", ")))
+		}
+	}
+
+	return update, changes
+}
+
+// newRecord construye el registro a crear con sus ajustes deseados
+// (TTL automático, sin proxy y sin comentario si no se configuran)
+func newRecord(desired Record, caps provider.Capabilities, recordType, content string) provider.Record {
+	record := provider.Record{
+		Type:    recordType,
+		Name:    desired.Name,
+		Content: content,
+		TTL:     1,
+	}
+	if caps.TTL && desired.TTL != 0 {
+		record.TTL = desired.TTL
+	}
+	if caps.Proxied && desired.Proxied != nil {
+		record.Proxied = *desired.Proxied
+	}
+	if caps.Comment && desired.Comment != nil {
+		record.Comment = *desired.Comment
+	}
+	if caps.Tags {
+		record.Tags = desired.Tags
+	}
+	return record
+}
+
+// formatTTL muestra el TTL en segundos o "auto" para el TTL automático o heredado
+func formatTTL(ttl int) string {
+	if ttl <= 1 {
+		return "auto"
+	}
+	return fmt.Sprintf("%ds", ttl)
+}
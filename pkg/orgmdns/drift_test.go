@@ -0,0 +1,162 @@
+package orgmdns
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+func TestDiffSettings(t *testing.T) {
+	yes, no := true, false
+	comment := "router"
+	all := provider.Capabilities{TTL: true, Proxied: true, Comment: true, Tags: true}
+
+	tests := []struct {
+		name        string
+		desired     Record
+		current     provider.Record
+		caps        provider.Capabilities
+		wantChanges []string
+		wantUpdate  provider.RecordUpdate
+	}{
+		{
+			name:    "sin ajustes gestionados",
+			desired: Record{},
+			current: provider.Record{TTL: 300, Proxied: true, Comment: "x", Tags: []string{"a"}},
+			caps:    all,
+		},
+		{
+			name:        "TTL distinto",
+			desired:     Record{TTL: 60},
+			current:     provider.Record{TTL: 300},
+			caps:        all,
+			wantChanges: []string{"TTL: 300s -> 60s"},
+			wantUpdate:  provider.RecordUpdate{TTL: 60},
+		},
+		{
+			name:    "TTL no soportado",
+			desired: Record{TTL: 60},
+			current: provider.Record{TTL: 300},
+			caps:    provider.Capabilities{},
+		},
+		{
+			name:    "TTL automático guardado como el TTL por defecto del proveedor",
+			desired: Record{TTL: 1},
+			current: provider.Record{TTL: 300},
+			caps:    provider.Capabilities{TTL: true, DefaultTTL: 300},
+		},
+		{
+			name:    "TTL automático con TTL heredado de la zona",
+			desired: Record{TTL: 1},
+			current: provider.Record{TTL: 0},
+			caps:    provider.Capabilities{TTL: true},
+		},
+		{
+			name:        "TTL fijo sobre TTL heredado de la zona",
+			desired:     Record{TTL: 120},
+			current:     provider.Record{TTL: 0},
+			caps:        provider.Capabilities{TTL: true},
+			wantChanges: []string{"TTL: auto -> 120s"},
+			wantUpdate:  provider.RecordUpdate{TTL: 120},
+		},
+		{
+			name:    "con proxy el TTL no se compara",
+			desired: Record{TTL: 60},
+			current: provider.Record{TTL: 1, Proxied: true},
+			caps:    all,
+		},
+		{
+			name:        "desactivar el proxy y fijar TTL",
+			desired:     Record{TTL: 60, Proxied: &no},
+			current:     provider.Record{TTL: 1, Proxied: true},
+			caps:        all,
+			wantChanges: []string{"proxied: true -> false", "TTL: auto -> 60s"},
+			wantUpdate:  provider.RecordUpdate{TTL: 60, Proxied: &no},
+		},
+		{
+			name:        "activar el proxy",
+			desired:     Record{TTL: 60, Proxied: &yes},
+			current:     provider.Record{TTL: 300},
+			caps:        all,
+			wantChanges: []string{"proxied: false -> true"},
+			wantUpdate:  provider.RecordUpdate{Proxied: &yes},
+		},
+		{
+			name:        "comentario",
+			desired:     Record{Comment: &comment},
+			current:     provider.Record{Comment: "viejo"},
+			caps:        all,
+			wantChanges: []string{`comentario: "viejo" -> "router"`},
+			wantUpdate:  provider.RecordUpdate{Comment: &comment},
+		},
+		{
+			name:    "etiquetas en otro orden",
+			desired: Record{Tags: []string{"team:infra", "env:prod"}},
+			current: provider.Record{Tags: []string{"env:prod", "team:infra"}},
+			caps:    all,
+		},
+		{
+			name:        "etiquetas distintas",
+			desired:     Record{Tags: []string{"env:prod"}},
+			current:     provider.Record{Tags: []string{"env:dev", "team:infra"}},
+			caps:        all,
+			wantChanges: []string{"etiquetas: [env:dev, team:infra] -> [env:prod]"},
+			wantUpdate:  provider.RecordUpdate{Tags: &[]string{"env:prod"}},
+		},
+		{
+			name:        "quitar etiquetas",
+			desired:     Record{Tags: []string{}},
+			current:     provider.Record{Tags: []string{"env:dev"}},
+			caps:        all,
+			wantChanges: []string{"etiquetas: [env:dev] -> []"},
+			wantUpdate:  provider.RecordUpdate{Tags: &[]string{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update, changes := diffSettings(tt.desired, tt.current, tt.caps)
+			if fmt.Sprint(changes) != fmt.Sprint(tt.wantChanges) {
+				t.Errorf("cambios = %q, want %q", changes, tt.wantChanges)
+			}
+			if describeUpdate(update) != describeUpdate(tt.wantUpdate) {
+				t.Errorf("actualización = %s, want %s", describeUpdate(update), describeUpdate(tt.wantUpdate))
+			}
+		})
+	}
+}
+
+func TestNewRecord(t *testing.T) {
+	yes := true
+	comment := "router"
+	desired := Record{Name: "home.example.com", TTL: 300, Proxied: &yes, Comment: &comment, Tags: []string{"env:prod"}}
+
+	got := newRecord(desired, provider.Capabilities{TTL: true, Proxied: true, Comment: true, Tags: true}, RecordTypeA, testIPv4)
+	want := provider.Record{Type: "A", Name: "home.example.com", Content: testIPv4, TTL: 300, Proxied: true, Comment: "router", Tags: []string{"env:prod"}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("registro = %+v, want %+v", got, want)
+	}
+
+	// Sin capacidades solo se usa TTL automático
+	got = newRecord(desired, provider.Capabilities{}, RecordTypeA, testIPv4)
+	want = provider.Record{Type: "A", Name: "home.example.com", Content: testIPv4, TTL: 1}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("registro sin capacidades = %+v, want %+v", got, want)
+	}
+}
+
+// describeUpdate muestra los campos de una actualización sin punteros
+func describeUpdate(u provider.RecordUpdate) string {
+	s := fmt.Sprintf("content=%q ttl=%d", u.Content, u.TTL)
+	if u.Proxied != nil {
+		s += fmt.Sprintf(" proxied=%t", *u.Proxied)
+	}
+	if u.Comment != nil {
+		s += fmt.Sprintf(" comment=%q", *u.Comment)
+	}
+	if u.Tags != nil {
+		s += fmt.Sprintf(" tags=%q", *u.Tags)
+	}
+	return s
+}
@@ -0,0 +1,88 @@
+package orgmdns
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// handleAPIError decide qué hacer ante un error de un proveedor DNS.
+// Retorna true si se deben detener las operaciones del proveedor en el ciclo actual.
+//   - Autenticación/permisos: se alerta por correo (una vez) y se detiene.
+//   - Rate limit o proveedor no disponible: se detiene y se reintenta en el siguiente ciclo.
+//   - Resto: se continúa y se reintenta en el siguiente ciclo.
+func (u *Updater) handleAPIError(ctx context.Context, providerName string, err error) bool {
+	switch {
+	case provider.IsAuthError(err):
+		u.logger.Error(fmt.Sprintf("Error de autenticación con %s: se detienen sus operaciones en este ciclo. Verifica las credenciales y sus permisos", providerName))
+		if !u.authAlertSent {
+			msg := fmt.Sprintf("El proveedor DNS %s rechazó las credenciales o no tienen permisos suficientes.\n\n%v", providerName, err)
+			if notifyErr := u.notifier.SendErrorNotification(ctx, msg); notifyErr != nil {
+				u.logger.Error(fmt.Sprintf("Error enviando correo de error: %v", notifyErr))
+			} else {
+				u.authAlertSent = true
+			}
+		}
+		return true
+	case provider.IsRateLimited(err):
+		u.logger.Error(fmt.Sprintf("Rate limit de %s excedido: se detienen sus operaciones y se reintentará en el siguiente ciclo", providerName))
+		return true
+	case provider.IsUnavailable(err):
+		u.logger.Error(fmt.Sprintf("Proveedor %s no disponible: se detienen sus operaciones y se reintentará en el siguiente ciclo", providerName))
+		return true
+	}
+	return false
+}
+
+// handleRecordError decide qué hacer ante un error al procesar un registro.
+// Además de los casos de handleAPIError, un error de validación desactiva el
+// registro hasta reiniciar (reintentarlo fallaría igual) y se alerta por correo.
+// Retorna true si se deben detener las operaciones del proveedor en el ciclo actual.
+func (u *Updater) handleRecordError(ctx context.Context, providerName string, key recordKey, err error) bool {
+	if u.handleAPIError(ctx, providerName, err) {
+		return true
+	}
+
+	switch {
+	case provider.IsValidation(err):
+		reason := fmt.Sprintf("%s rechazó los datos del registro: %v", providerName, err)
+		u.disabled[key] = reason
+		u.logger.Error(fmt.Sprintf("Registro %s (%s) desactivado hasta reiniciar: %s", key.name, key.recordType, reason))
+		msg := fmt.Sprintf("El registro %s (%s) se desactivó hasta reiniciar orgmdns.\n\n%s\n\nRevisa su configuración.", key.name, key.recordType, reason)
+		if notifyErr := u.notifier.SendErrorNotification(ctx, msg); notifyErr != nil {
+			u.logger.Error(fmt.Sprintf("Error enviando correo de error: %v", notifyErr))
+		}
+	case provider.IsConflict(err):
+		// Otro cliente modificó el registro entre el snapshot y la actualización
+		u.logger.Info(fmt.Sprintf("Registro %s (%s) modificado en %s durante el ciclo, se reintentará en el siguiente", key.name, key.recordType, providerName))
+	case provider.IsNotFound(err):
+		// El registro desapareció entre el snapshot y la actualización
+		u.logger.Info(fmt.Sprintf("Registro %s (%s) no encontrado en %s, se reintentará en el siguiente ciclo", key.name, key.recordType, providerName))
+	}
+	return false
+}
+
+// reportUnreachable avisa (una vez mientras la familia siga sin IP pública, o si la
+// dirección cambia) que la IP detectada no es pública y el sitio no es alcanzable
+// desde internet. Los registros DNS conservan su valor.
+func (u *Updater) reportUnreachable(ctx context.Context, recordType string, addrErr *AddressError) {
+	if addrErr.CGNAT {
+		u.logger.Error(fmt.Sprintf("El proveedor de internet usa carrier-grade NAT (%s): el sitio no es alcanzable desde internet por %s", addrErr.IP, familyName(recordType)))
+	}
+	if u.dryRun || u.unreachable[recordType] == addrErr.IP {
+		return
+	}
+
+	notifier, ok := u.notifier.(UnreachableNotifier)
+	if !ok {
+		u.unreachable[recordType] = addrErr.IP
+		return
+	}
+	if err := notifier.SendUnreachableNotification(ctx, recordType, addrErr.IP, addrErr.Range, addrErr.CGNAT); err != nil {
+		u.logger.Error(fmt.Sprintf("Error enviando correo de sitio no alcanzable: %v", err))
+		return
+	}
+	u.logger.Info(fmt.Sprintf("Correo de sitio no alcanzable enviado (%s %s)", familyName(recordType), addrErr.IP))
+	u.unreachable[recordType] = addrErr.IP
+}
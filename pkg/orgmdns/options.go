@@ -0,0 +1,277 @@
+package orgmdns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/osmargm1202/orgmdns/internal/ip"
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// Valores por defecto del Updater (los mismos que SLEEP_TIME y SHUTDOWN_GRACE)
+const (
+	DefaultInterval      = 10 * time.Minute
+	DefaultShutdownGrace = 30 * time.Second
+)
+
+// Option configura un Updater en New
+type Option func(*Updater) error
+
+// WithProvider registra un proveedor DNS con su nombre (p.Name()). El primer
+// proveedor registrado es el proveedor por defecto salvo que se use WithDefaultProvider.
+func WithProvider(p DNSProvider) Option {
+	return func(u *Updater) error {
+		if p == nil {
+			return errors.New("proveedor DNS nil")
+		}
+		name := p.Name()
+		if _, exists := u.providers[name]; exists {
+			return fmt.Errorf("proveedor DNS %s registrado dos veces", name)
+		}
+		u.providers[name] = p
+		if u.defaultProvider == "" {
+			u.defaultProvider = name
+		}
+		return nil
+	}
+}
+
+// WithDefaultProvider elige el proveedor de los registros que no están en ninguna
+// zona de WithZoneProvider
+func WithDefaultProvider(name string) Option {
+	return func(u *Updater) error {
+		u.defaultProvider = name
+		return nil
+	}
+}
+
+// WithZoneProvider asigna una zona (y sus subdominios) a un proveedor registrado
+func WithZoneProvider(zone, name string) Option {
+	return func(u *Updater) error {
+		zone = normalizeName(zone)
+		if zone == "" {
+			return errors.New("zona vacía en WithZoneProvider")
+		}
+		u.zoneProviders[zone] = name
+		return nil
+	}
+}
+
+// WithRecords agrega registros gestionados
+func WithRecords(records ...Record) Option {
+	return func(u *Updater) error {
+		for _, record := range records {
+			if strings.TrimSpace(record.Name) == "" {
+				return errors.New("registro sin nombre")
+			}
+			if len(record.Types) == 0 {
+				record.Types = []string{RecordTypeA}
+			}
+			for _, recordType := range record.Types {
+				if recordType != RecordTypeA && recordType != RecordTypeAAAA {
+					return fmt.Errorf("registro %s: tipo inválido %q (use A o AAAA)", record.Name, recordType)
+				}
+			}
+			u.records = append(u.records, record)
+		}
+		return nil
+	}
+}
+
+// WithIPList agrega una lista de IPs que debe contener la IP pública actual de
+// cada familia detectada (por ejemplo, una lista de IPs de Cloudflare usada en WAF)
+func WithIPList(list IPList) Option {
+	return func(u *Updater) error {
+		if list == nil {
+			return errors.New("lista de IPs nil")
+		}
+		u.ipLists = append(u.ipLists, list)
+		return nil
+	}
+}
+
+// WithIPSource reemplaza la fuente de IP pública (por defecto DefaultIPSource)
+func WithIPSource(source IPSource) Option {
+	return func(u *Updater) error {
+		if source == nil {
+			return errors.New("fuente de IP nil")
+		}
+		u.ipSource = source
+		return nil
+	}
+}
+
+// WithConnectivityCheck reemplaza la verificación de conexión a internet previa a
+// cada ciclo (por defecto una petición HTTP GET a https://www.google.com)
+func WithConnectivityCheck(check func(ctx context.Context) bool) Option {
+	return func(u *Updater) error {
+		if check == nil {
+			return errors.New("verificación de conexión nil")
+		}
+		u.checkConnection = check
+		return nil
+	}
+}
+
+// WithNotifier agrega un notificador; con varios, cada aviso se envía a todos.
+// Sin notificadores no se envían avisos.
+func WithNotifier(n Notifier) Option {
+	return func(u *Updater) error {
+		if n == nil {
+			return errors.New("notificador nil")
+		}
+		u.notifiers = append(u.notifiers, n)
+		return nil
+	}
+}
+
+// WithLogger reemplaza el logger (por defecto slog.Default())
+func WithLogger(logger *slog.Logger) Option {
+	return func(u *Updater) error {
+		if logger == nil {
+			return errors.New("logger nil")
+		}
+		u.logger = logger
+		return nil
+	}
+}
+
+// WithCreateMissing crea los registros que no existen en el proveedor
+func WithCreateMissing(create bool) Option {
+	return func(u *Updater) error {
+		u.createMissing = create
+		return nil
+	}
+}
+
+// WithInterval cambia la espera entre ciclos de Run
+func WithInterval(interval time.Duration) Option {
+	return func(u *Updater) error {
+		if interval <= 0 {
+			return fmt.Errorf("intervalo inválido: %v", interval)
+		}
+		u.interval = interval
+		return nil
+	}
+}
+
+// WithShutdownGrace cambia el tiempo que Run espera al trabajo en curso al cancelarse
+func WithShutdownGrace(grace time.Duration) Option {
+	return func(u *Updater) error {
+		if grace < 0 {
+			return fmt.Errorf("periodo de gracia inválido: %v", grace)
+		}
+		u.shutdownGrace = grace
+		return nil
+	}
+}
+
+// WithShutdownNotify envía un aviso al terminar Run
+func WithShutdownNotify(notify bool) Option {
+	return func(u *Updater) error {
+		u.shutdownNotify = notify
+		return nil
+	}
+}
+
+// WithDryRun hace que Run y RunOnce solo calculen y registren en el log el plan de
+// cada ciclo (ver Plan): no se escribe en los proveedores ni se envían avisos
+func WithDryRun(dryRun bool) Option {
+	return func(u *Updater) error {
+		u.dryRun = dryRun
+		return nil
+	}
+}
+
+// New crea un Updater. Requiere al menos un proveedor y un registro; cada registro
+// debe corresponder a un proveedor registrado.
+func New(opts ...Option) (*Updater, error) {
+	u := &Updater{
+		providers:        make(map[string]provider.DNSProvider),
+		zoneProviders:    make(map[string]string),
+		ipSource:         DefaultIPSource(),
+		checkConnection:  ip.CheckInternetConnection,
+		logger:           slog.Default(),
+		interval:         DefaultInterval,
+		shutdownGrace:    DefaultShutdownGrace,
+		disabled:         make(map[recordKey]string),
+		batchUnsupported: make(map[string]bool),
+		unreachable:      make(map[string]string),
+	}
+	for _, opt := range opts {
+		if err := opt(u); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(u.providers) == 0 {
+		return nil, errors.New("se requiere al menos un proveedor DNS (WithProvider)")
+	}
+	if len(u.records) == 0 {
+		return nil, errors.New("se requiere al menos un registro (WithRecords)")
+	}
+	for _, record := range u.records {
+		if name := u.providerFor(record.Name); u.providers[name] == nil {
+			return nil, fmt.Errorf("el registro %s usa el proveedor %s, que no está registrado", record.Name, name)
+		}
+	}
+
+	switch {
+	case u.dryRun || len(u.notifiers) == 0:
+		u.notifier = multiNotifier(nil)
+	case len(u.notifiers) == 1:
+		u.notifier = u.notifiers[0]
+	default:
+		u.notifier = multiNotifier(u.notifiers)
+	}
+
+	return u, nil
+}
+
+// providerFor retorna el proveedor del registro: el de la zona asignada que sea su
+// sufijo más largo o, si no hay, el proveedor por defecto
+func (u *Updater) providerFor(recordName string) string {
+	labels := strings.Split(normalizeName(recordName), ".")
+	for i := range labels {
+		if name, ok := u.zoneProviders[strings.Join(labels[i:], ".")]; ok {
+			return name
+		}
+	}
+	return u.defaultProvider
+}
+
+// managesType indica si algún registro gestiona el tipo indicado
+func (u *Updater) managesType(recordType string) bool {
+	for _, record := range u.records {
+		if record.HasType(recordType) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordNames retorna los nombres de los registros gestionados
+func (u *Updater) recordNames() []string {
+	names := make([]string, 0, len(u.records))
+	for _, record := range u.records {
+		names = append(names, record.Name)
+	}
+	return names
+}
+
+// familyName retorna el nombre de la familia de IP del tipo de registro
+func familyName(recordType string) string {
+	if recordType == RecordTypeAAAA {
+		return "IPv6"
+	}
+	return "IPv4"
+}
+
+// normalizeName pasa un nombre DNS a minúsculas y sin punto final
+func normalizeName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
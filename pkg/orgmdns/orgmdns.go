@@ -0,0 +1,182 @@
+// Package orgmdns mantiene registros DNS apuntando a la IP pública actual.
+//
+// Es la API pública de orgmdns para usarlo desde otros programas en Go: un
+// Updater se construye con New y opciones (proveedores DNS, fuente de IP,
+// notificadores y logger) y se ejecuta con RunOnce (un ciclo) o Run (bucle hasta
+// que se cancele el contexto). El binario cmd/orgmdns es un envoltorio que arma
+// las opciones desde variables de entorno.
+//
+//	updater, err := orgmdns.New(
+//		orgmdns.WithProvider(orgmdns.NewCloudflareProvider(token, "", nil)),
+//		orgmdns.WithRecords(orgmdns.Record{Name: "home.example.com", Types: []string{orgmdns.RecordTypeA}}),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	return updater.Run(ctx)
+package orgmdns
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/osmargm1202/orgmdns/internal/config"
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// Tipos de registro DNS soportados
+const (
+	RecordTypeA    = config.RecordTypeA    // IPv4
+	RecordTypeAAAA = config.RecordTypeAAAA // IPv6
+)
+
+// Record es un registro gestionado: nombre, familias y ajustes deseados
+// (TTL, proxied, comentario y etiquetas; los no configurados no se gestionan)
+type Record = config.Record
+
+// Tipos comunes de los proveedores DNS. Cualquier tipo que implemente DNSProvider
+// (y opcionalmente CycleAware, Preflighter y Batcher) se puede registrar con WithProvider.
+type (
+	DNSProvider  = provider.DNSProvider
+	CycleAware   = provider.CycleAware
+	Preflighter  = provider.Preflighter
+	Batcher      = provider.Batcher
+	RecordChange = provider.RecordChange
+	Zone         = provider.Zone
+	DNSRecord    = provider.Record
+	RecordUpdate = provider.RecordUpdate
+	ListFilter   = provider.ListFilter
+	Capabilities = provider.Capabilities
+	IPList       = provider.IPList
+	IPListChange = provider.IPListChange
+)
+
+// Categorías de error de los proveedores. Los proveedores propios deben envolverlas
+// (fmt.Errorf("...: %w", orgmdns.ErrAuth)) para que el Updater reaccione igual que
+// con los proveedores incluidos.
+var (
+	ErrAuth        = provider.ErrAuth
+	ErrNotFound    = provider.ErrNotFound
+	ErrRateLimited = provider.ErrRateLimited
+	ErrValidation  = provider.ErrValidation
+	ErrUnavailable = provider.ErrUnavailable
+	ErrConflict    = provider.ErrConflict
+
+	ErrBatchUnsupported = provider.ErrBatchUnsupported
+	ErrUnverified       = provider.ErrUnverified
+)
+
+// ApplyUpdate retorna el registro resultante de aplicar update sobre current
+func ApplyUpdate(current DNSRecord, update RecordUpdate) DNSRecord {
+	return provider.ApplyUpdate(current, update)
+}
+
+// IPSource obtiene la IP pública de una familia (RecordTypeA -> IPv4, RecordTypeAAAA -> IPv6)
+type IPSource interface {
+	PublicIP(ctx context.Context, recordType string) (string, error)
+}
+
+// IPSourceFunc adapta una función a IPSource
+type IPSourceFunc func(ctx context.Context, recordType string) (string, error)
+
+func (f IPSourceFunc) PublicIP(ctx context.Context, recordType string) (string, error) {
+	return f(ctx, recordType)
+}
+
+// Notifier recibe los avisos del Updater (por ejemplo, por correo). Los errores
+// se registran en el log y no detienen el ciclo.
+type Notifier interface {
+	SendDNSUpdateNotification(ctx context.Context, recordName, recordType, oldIP, newIP string, changes []string) error
+	SendDNSSettingsNotification(ctx context.Context, recordName, recordType string, changes []string) error
+	SendDNSCreateNotification(ctx context.Context, recordName, recordType, ip string) error
+	SendStartupNotification(ctx context.Context, currentIPv4, currentIPv6 string, recordNames []string) error
+	SendConnectionRestoredNotification(ctx context.Context, duration time.Duration) error
+	SendShutdownNotification(ctx context.Context, uptime time.Duration) error
+	SendErrorNotification(ctx context.Context, errorMsg string) error
+}
+
+// IPListNotifier lo implementan los notificadores que avisan de cambios en las
+// listas de IPs (WithIPList); los demás no reciben esos avisos
+type IPListNotifier interface {
+	SendIPListUpdateNotification(ctx context.Context, listName, recordType, added string, removed []string) error
+}
+
+// UnreachableNotifier lo implementan los notificadores que avisan cuando la IP
+// detectada no es pública (ver AddressError); reason es el rango en el que cae
+type UnreachableNotifier interface {
+	SendUnreachableNotification(ctx context.Context, recordType, address, reason string, cgnat bool) error
+}
+
+// multiNotifier reenvía cada aviso a varios notificadores
+type multiNotifier []Notifier
+
+func (m multiNotifier) each(fn func(Notifier) error) error {
+	var errs []error
+	for _, n := range m {
+		if err := fn(n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m multiNotifier) SendDNSUpdateNotification(ctx context.Context, recordName, recordType, oldIP, newIP string, changes []string) error {
+	return m.each(func(n Notifier) error {
+		return n.SendDNSUpdateNotification(ctx, recordName, recordType, oldIP, newIP, changes)
+	})
+}
+
+func (m multiNotifier) SendDNSSettingsNotification(ctx context.Context, recordName, recordType string, changes []string) error {
+	return m.each(func(n Notifier) error {
+		return n.SendDNSSettingsNotification(ctx, recordName, recordType, changes)
+	})
+}
+
+func (m multiNotifier) SendDNSCreateNotification(ctx context.Context, recordName, recordType, ip string) error {
+	return m.each(func(n Notifier) error {
+		return n.SendDNSCreateNotification(ctx, recordName, recordType, ip)
+	})
+}
+
+func (m multiNotifier) SendStartupNotification(ctx context.Context, currentIPv4, currentIPv6 string, recordNames []string) error {
+	return m.each(func(n Notifier) error {
+		return n.SendStartupNotification(ctx, currentIPv4, currentIPv6, recordNames)
+	})
+}
+
+func (m multiNotifier) SendConnectionRestoredNotification(ctx context.Context, duration time.Duration) error {
+	return m.each(func(n Notifier) error {
+		return n.SendConnectionRestoredNotification(ctx, duration)
+	})
+}
+
+func (m multiNotifier) SendShutdownNotification(ctx context.Context, uptime time.Duration) error {
+	return m.each(func(n Notifier) error {
+		return n.SendShutdownNotification(ctx, uptime)
+	})
+}
+
+func (m multiNotifier) SendErrorNotification(ctx context.Context, errorMsg string) error {
+	return m.each(func(n Notifier) error {
+		return n.SendErrorNotification(ctx, errorMsg)
+	})
+}
+
+func (m multiNotifier) SendIPListUpdateNotification(ctx context.Context, listName, recordType, added string, removed []string) error {
+	return m.each(func(n Notifier) error {
+		if ln, ok := n.(IPListNotifier); ok {
+			return ln.SendIPListUpdateNotification(ctx, listName, recordType, added, removed)
+		}
+		return nil
+	})
+}
+
+func (m multiNotifier) SendUnreachableNotification(ctx context.Context, recordType, address, reason string, cgnat bool) error {
+	return m.each(func(n Notifier) error {
+		if un, ok := n.(UnreachableNotifier); ok {
+			return un.SendUnreachableNotification(ctx, recordType, address, reason, cgnat)
+		}
+		return nil
+	})
+}
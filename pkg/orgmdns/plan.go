@@ -0,0 +1,224 @@
+package orgmdns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// Acciones de un registro en un Plan
+const (
+	PlanCreate = "create" // el registro no existe y se crearía
+	PlanUpdate = "update" // la IP o algún ajuste difiere y se actualizaría
+	PlanSkip   = "skip"   // no se tocaría (al día, desactivado, sin IP o con error)
+)
+
+// PlannedRecord es lo que un ciclo haría con un registro y familia
+type PlannedRecord struct {
+	Provider  string   `json:"provider"`
+	Zone      string   `json:"zone,omitempty"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Action    string   `json:"action"`
+	CurrentIP string   `json:"current_ip,omitempty"` // IP en el DNS ("" si no existe)
+	NewIP     string   `json:"new_ip,omitempty"`     // IP que se escribiría
+	Changes   []string `json:"changes,omitempty"`    // ajustes que se corregirían
+	Reason    string   `json:"reason,omitempty"`     // motivo de un skip
+}
+
+// Plan es el resultado de un ciclo en modo dry-run: los registros que se crearían,
+// actualizarían u omitirían y los cambios en las listas de IPs. No se escribe en los
+// proveedores ni se envían avisos.
+type Plan struct {
+	PublicIPs map[string]string `json:"public_ips"` // tipo de registro -> IP detectada
+	Records   []PlannedRecord   `json:"records"`
+	IPLists   []IPListChange    `json:"ip_lists,omitempty"`
+}
+
+// HasChanges indica si el plan crearía o actualizaría algo
+func (p *Plan) HasChanges() bool {
+	for _, record := range p.Records {
+		if record.Action != PlanSkip {
+			return true
+		}
+	}
+	return len(p.IPLists) > 0
+}
+
+// WriteJSON escribe el plan como JSON indentado
+func (p *Plan) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(p)
+}
+
+// WriteTable escribe el plan como una tabla de texto
+func (p *Plan) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ACCIÓN\tREGISTRO\tTIPO\tPROVEEDOR\tZONA\tDNS\tNUEVA\tDETALLE")
+	for _, record := range p.Records {
+		detail := record.Reason
+		if len(record.Changes) > 0 {
+			detail = strings.Join(record.Changes, "; ")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", record.Action, record.Name, record.Type,
+			record.Provider, orDash(record.Zone), orDash(record.CurrentIP), orDash(record.NewIP), orDash(detail))
+	}
+	if len(p.IPLists) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "LISTA\tTIPO\tAGREGAR\tELIMINAR")
+		for _, change := range p.IPLists {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", change.List, change.RecordType, orDash(change.Added), orDash(strings.Join(change.Removed, ", ")))
+		}
+	}
+	return tw.Flush()
+}
+
+// Plan ejecuta un ciclo en modo dry-run y retorna lo que se haría: consulta la IP
+// pública y los proveedores, pero no crea ni actualiza registros, no modifica las
+// listas de IPs y no envía avisos. El error son los errores del ciclo como en
+// RunOnce; los registros que no se llegaron a evaluar aparecen como skip.
+func (u *Updater) Plan(ctx context.Context) (*Plan, error) {
+	dryRun, notifier, shutdown := u.dryRun, u.notifier, u.shutdown
+	startupEmailSent, authAlertSent := u.startupEmailSent, u.authAlertSent
+	u.dryRun, u.notifier, u.shutdown = true, multiNotifier(nil), ctx
+	defer func() {
+		u.dryRun, u.notifier, u.shutdown = dryRun, notifier, shutdown
+		u.startupEmailSent, u.authAlertSent = startupEmailSent, authAlertSent
+		u.plan = nil
+	}()
+
+	err := u.runCycle(ctx)
+	return u.plan, err
+}
+
+// planRecordOp agrega al plan del ciclo un cambio planificado
+func (u *Updater) planRecordOp(target *zoneTarget, op *recordOp) {
+	if u.plan == nil {
+		return
+	}
+	planned := PlannedRecord{
+		Provider: target.provider.Name(),
+		Zone:     target.zone.Name,
+		Name:     op.desired.Name,
+		Type:     op.recordType,
+		Action:   PlanUpdate,
+		NewIP:    op.newIP,
+		Changes:  op.changes,
+	}
+	if op.create {
+		planned.Action = PlanCreate
+	} else {
+		planned.CurrentIP = op.oldIP
+	}
+	u.plan.Records = append(u.plan.Records, planned)
+}
+
+// planSkip agrega al plan del ciclo un registro que no se tocaría y el motivo
+func (u *Updater) planSkip(target *zoneTarget, snapshot zoneSnapshot, recordName, recordType, reason string) {
+	if u.plan == nil {
+		return
+	}
+	planned := PlannedRecord{
+		Provider: target.provider.Name(),
+		Zone:     target.zone.Name,
+		Name:     recordName,
+		Type:     recordType,
+		Action:   PlanSkip,
+		Reason:   reason,
+	}
+	if record, ok := snapshot.get(recordName, recordType); ok {
+		planned.CurrentIP = record.Content
+	}
+	u.plan.Records = append(u.plan.Records, planned)
+}
+
+// planIPLists agrega al plan los cambios que syncIPLists aplicaría en cada lista
+func (u *Updater) planIPLists(ctx context.Context, currentIPs map[string]string) error {
+	var errs []error
+	for _, list := range u.ipLists {
+		name := list.Name()
+		planner, ok := list.(provider.IPListPlanner)
+		if !ok {
+			u.logger.Info(fmt.Sprintf("La lista de IPs %s no puede calcular cambios sin aplicarlos, se omite en el plan", name))
+			continue
+		}
+		for _, recordType := range []string{RecordTypeA, RecordTypeAAAA} {
+			currentIP, ok := currentIPs[recordType]
+			if !ok {
+				continue
+			}
+			change, err := planner.PlanIP(ctx, recordType, currentIP)
+			if err != nil {
+				u.logger.Error(fmt.Sprintf("Error calculando cambios de la lista de IPs %s (%s): %v", name, recordType, err))
+				errs = append(errs, fmt.Errorf("lista de IPs %s (%s): %w", name, recordType, err))
+				continue
+			}
+			if change != nil {
+				u.plan.IPLists = append(u.plan.IPLists, *change)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// finishPlan marca como skip los registros que el ciclo no llegó a evaluar y
+// registra el plan en el log
+func (u *Updater) finishPlan() {
+	evaluated := make(map[recordKey]bool, len(u.plan.Records))
+	for _, planned := range u.plan.Records {
+		evaluated[newRecordKey(planned.Name, planned.Type)] = true
+	}
+	for _, record := range u.records {
+		for _, recordType := range record.Types {
+			if evaluated[newRecordKey(record.Name, recordType)] {
+				continue
+			}
+			u.plan.Records = append(u.plan.Records, PlannedRecord{
+				Provider: u.providerFor(record.Name),
+				Name:     record.Name,
+				Type:     recordType,
+				Action:   PlanSkip,
+				Reason:   "no evaluado (ver errores del ciclo)",
+			})
+		}
+	}
+
+	var creates, updates, skips int
+	for _, planned := range u.plan.Records {
+		switch planned.Action {
+		case PlanCreate:
+			creates++
+			u.logger.Info(fmt.Sprintf("Plan: crear %s (%s) en %s con IP %s", planned.Name, planned.Type, planned.Provider, planned.NewIP))
+		case PlanUpdate:
+			updates++
+			detail := ""
+			if len(planned.Changes) > 0 {
+				detail = " (" + strings.Join(planned.Changes, "; ") + ")"
+			}
+			u.logger.Info(fmt.Sprintf("Plan: actualizar %s (%s) en %s: %s -> %s%s", planned.Name, planned.Type, planned.Provider, planned.CurrentIP, planned.NewIP, detail))
+		default:
+			skips++
+			u.logger.Debug(fmt.Sprintf("Plan: omitir %s (%s): %s", planned.Name, planned.Type, planned.Reason))
+		}
+	}
+	for _, change := range u.plan.IPLists {
+		u.logger.Info(fmt.Sprintf("Plan: lista de IPs %s (%s): agregar %q, eliminar [%s]", change.List, change.RecordType, change.Added, strings.Join(change.Removed, ", ")))
+	}
+	u.logger.Info(fmt.Sprintf("Plan (dry-run): %d creaciones, %d actualizaciones, %d sin cambios, %d cambios en listas de IPs. No se aplicó ningún cambio",
+		creates, updates, skips, len(u.plan.IPLists)))
+}
+
+// orDash retorna "-" para los valores vacíos de la tabla
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,140 @@
+package orgmdns
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// testIPList es una lista de IPs que registra las escrituras y puede planificar
+type testIPList struct {
+	entries []string
+	syncs   int
+}
+
+func (l *testIPList) Name() string { return "waf" }
+
+func (l *testIPList) SyncIP(ctx context.Context, recordType, ip string) (*provider.IPListChange, error) {
+	l.syncs++
+	change, _ := l.PlanIP(ctx, recordType, ip)
+	if change != nil {
+		l.entries = []string{ip}
+	}
+	return change, nil
+}
+
+func (l *testIPList) PlanIP(ctx context.Context, recordType, ip string) (*provider.IPListChange, error) {
+	if len(l.entries) == 1 && l.entries[0] == ip {
+		return nil, nil
+	}
+	return &provider.IPListChange{List: l.Name(), RecordType: recordType, Added: ip, Removed: l.entries}, nil
+}
+
+func TestPlanDoesNotWrite(t *testing.T) {
+	p := newFakeProvider(
+		provider.Record{ID: "r1", Type: "A", Name: "home.example.com", Content: "198.51.100.1", TTL: 300},
+		provider.Record{ID: "r2", Type: "A", Name: "nas.example.com", Content: testIPv4, TTL: 300},
+	)
+	notifier := &fakeNotifier{}
+	list := &testIPList{entries: []string{"198.51.100.1"}}
+	u := newTestUpdater(t, p, notifier,
+		WithCreateMissing(true),
+		WithIPList(list),
+		WithRecords(
+			Record{Name: "home.example.com", TTL: 60},
+			Record{Name: "nas.example.com"},
+			Record{Name: "new.example.com"},
+		),
+	)
+
+	plan, err := u.Plan(context.Background())
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	if p.writes() != 0 || list.syncs != 0 || len(notifier.sent) != 0 {
+		t.Fatalf("escrituras = %d, sincronizaciones = %d, avisos = %v: el plan no debe escribir ni avisar", p.writes(), list.syncs, notifier.sent)
+	}
+
+	want := []PlannedRecord{
+		{Provider: "fake", Zone: "example.com", Name: "home.example.com", Type: "A", Action: PlanUpdate, CurrentIP: "198.51.100.1", NewIP: testIPv4, Changes: []string{"TTL: 300s -> 60s"}},
+		{Provider: "fake", Zone: "example.com", Name: "nas.example.com", Type: "A", Action: PlanSkip, CurrentIP: testIPv4, Reason: "al día"},
+		{Provider: "fake", Zone: "example.com", Name: "new.example.com", Type: "A", Action: PlanCreate, NewIP: testIPv4},
+	}
+	if fmt.Sprintf("%+v", plan.Records) != fmt.Sprintf("%+v", want) {
+		t.Errorf("registros del plan = %+v\nwant %+v", plan.Records, want)
+	}
+	if len(plan.IPLists) != 1 || plan.IPLists[0].Added != testIPv4 || plan.PublicIPs[RecordTypeA] != testIPv4 {
+		t.Errorf("plan = %+v", *plan)
+	}
+	if !plan.HasChanges() {
+		t.Error("HasChanges() = false")
+	}
+
+	// Plan deja el Updater como estaba: el siguiente ciclo aplica y avisa
+	if u.plan != nil || u.dryRun || u.shutdown != nil {
+		t.Errorf("estado tras Plan: plan = %v, dryRun = %v, shutdown = %v", u.plan, u.dryRun, u.shutdown)
+	}
+	if err := u.RunOnce(context.Background()); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if len(p.updates) != 1 || len(p.creates) != 1 || list.syncs != 1 {
+		t.Errorf("actualizaciones = %d, creaciones = %d, sincronizaciones = %d", len(p.updates), len(p.creates), list.syncs)
+	}
+	if fmt.Sprint(notifier.sent) != "[startup update home.example.com create new.example.com]" {
+		t.Errorf("avisos = %v", notifier.sent)
+	}
+}
+
+func TestPlanWithBatcher(t *testing.T) {
+	p := &fakeBatcher{fakeProvider: newFakeProvider(
+		provider.Record{ID: "r1", Type: "A", Name: "a.example.com", Content: "198.51.100.1"},
+		provider.Record{ID: "r2", Type: "A", Name: "b.example.com", Content: "198.51.100.1"},
+	)}
+	u := newTestUpdater(t, p, &fakeNotifier{}, WithRecords(Record{Name: "a.example.com"}, Record{Name: "b.example.com"}))
+
+	plan, err := u.Plan(context.Background())
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if p.batches != 0 || p.writes() != 0 {
+		t.Errorf("lotes = %d, escrituras = %d, want ninguno", p.batches, p.writes())
+	}
+	if len(plan.Records) != 2 || plan.Records[0].Action != PlanUpdate || plan.Records[1].Action != PlanUpdate {
+		t.Errorf("registros del plan = %+v", plan.Records)
+	}
+}
+
+func TestDryRunOption(t *testing.T) {
+	p := newFakeProvider(provider.Record{ID: "r1", Type: "A", Name: "home.example.com", Content: "198.51.100.1"})
+	notifier := &fakeNotifier{}
+	u := newTestUpdater(t, p, notifier, WithDryRun(true), WithCreateMissing(true), WithRecords(
+		Record{Name: "home.example.com"},
+		Record{Name: "new.example.com"},
+	))
+
+	for i := 0; i < 2; i++ {
+		if err := u.RunOnce(context.Background()); err != nil {
+			t.Fatalf("error inesperado: %v", err)
+		}
+	}
+	if p.writes() != 0 || len(notifier.sent) != 0 {
+		t.Errorf("escrituras = %d, avisos = %v: dry-run no debe escribir ni avisar", p.writes(), notifier.sent)
+	}
+}
+
+func TestPlanSkipsUnevaluatedRecords(t *testing.T) {
+	p := newFakeProvider()
+	p.listErr = fmt.Errorf("timeout: %w", provider.ErrUnavailable)
+	u := newTestUpdater(t, p, &fakeNotifier{}, WithRecords(Record{Name: "home.example.com", Types: []string{RecordTypeA, RecordTypeAAAA}}))
+
+	plan, err := u.Plan(context.Background())
+	if err == nil {
+		t.Fatal("error = nil, want el error del listado")
+	}
+	if len(plan.Records) != 2 || plan.Records[0].Action != PlanSkip || plan.Records[1].Action != PlanSkip || plan.HasChanges() {
+		t.Errorf("registros del plan = %+v", plan.Records)
+	}
+}
@@ -0,0 +1,97 @@
+package orgmdns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// recordKey identifica un registro por nombre normalizado y tipo
+type recordKey struct {
+	name       string
+	recordType string
+}
+
+// zoneSnapshot es el estado de los registros A/AAAA de una zona en un ciclo
+type zoneSnapshot map[recordKey]provider.Record
+
+func newRecordKey(name, recordType string) recordKey {
+	return recordKey{
+		name:       strings.TrimSuffix(strings.ToLower(name), "."),
+		recordType: recordType,
+	}
+}
+
+// get busca el registro por nombre y tipo en el snapshot
+func (s zoneSnapshot) get(name, recordType string) (provider.Record, bool) {
+	record, ok := s[newRecordKey(name, recordType)]
+	return record, ok
+}
+
+// loadSnapshot obtiene el estado de la zona una sola vez por ciclo.
+// Si sus registros solo gestionan una familia se filtra por tipo en el proveedor;
+// si se gestionan ambas se lista la zona completa y se filtra localmente.
+// Los proveedores que no pueden listar la zona se consultan registro por registro.
+func (u *Updater) loadSnapshot(ctx context.Context, target *zoneTarget, records []Record) (zoneSnapshot, error) {
+	if !target.provider.Capabilities().ListZone {
+		return u.loadSnapshotByRecord(ctx, target, records)
+	}
+
+	filter := provider.ListFilter{}
+	managesA, managesAAAA := false, false
+	for _, record := range records {
+		managesA = managesA || record.HasType(RecordTypeA)
+		managesAAAA = managesAAAA || record.HasType(RecordTypeAAAA)
+	}
+	if managesA && !managesAAAA {
+		filter.Type = RecordTypeA
+	} else if managesAAAA && !managesA {
+		filter.Type = RecordTypeAAAA
+	}
+
+	zoneRecords, err := target.provider.ListRecords(ctx, target.zone, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error listando registros DNS de la zona: %w", err)
+	}
+
+	snapshot := make(zoneSnapshot)
+	for _, record := range zoneRecords {
+		if record.Type != RecordTypeA && record.Type != RecordTypeAAAA {
+			continue
+		}
+		key := newRecordKey(record.Name, record.Type)
+		if _, exists := snapshot[key]; exists {
+			// Varios registros con el mismo nombre y tipo: se gestiona el primero
+			u.logger.Debug(fmt.Sprintf("Registro duplicado %s (%s) ignorado (ID: %s)", record.Name, record.Type, record.ID))
+			continue
+		}
+		snapshot[key] = record
+	}
+
+	u.logger.Debug(fmt.Sprintf("Snapshot de zona cargado: %d registros A/AAAA", len(snapshot)))
+
+	return snapshot, nil
+}
+
+// loadSnapshotByRecord arma el snapshot consultando cada registro y familia gestionada
+func (u *Updater) loadSnapshotByRecord(ctx context.Context, target *zoneTarget, records []Record) (zoneSnapshot, error) {
+	snapshot := make(zoneSnapshot)
+	for _, desired := range records {
+		for _, recordType := range desired.Types {
+			record, err := target.provider.GetRecord(ctx, target.zone, desired.Name, recordType)
+			if provider.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("error consultando registro %s (%s): %w", desired.Name, recordType, err)
+			}
+			snapshot[newRecordKey(record.Name, record.Type)] = *record
+		}
+	}
+
+	u.logger.Debug(fmt.Sprintf("Snapshot de zona cargado por registro: %d registros A/AAAA", len(snapshot)))
+
+	return snapshot, nil
+}
@@ -0,0 +1,683 @@
+package orgmdns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/osmargm1202/orgmdns/internal/ip"
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// Tiempo máximo para enviar el correo de apagado, independiente del periodo de gracia
+const shutdownNotifyTimeout = 30 * time.Second
+
+// ErrShutdownTimeout indica que el trabajo en curso no terminó dentro del periodo de gracia
+var ErrShutdownTimeout = errors.New("el trabajo en curso no terminó dentro del periodo de gracia")
+
+// ErrNoConnection indica que el ciclo se omitió por falta de conexión a internet
+var ErrNoConnection = errors.New("no hay conexión a internet")
+
+// ErrNoPublicIP indica que no se pudo obtener la IP pública de ninguna familia gestionada
+var ErrNoPublicIP = errors.New("no se pudo obtener la IP pública")
+
+// Updater mantiene los registros DNS configurados apuntando a la IP pública actual.
+// Se crea con New; no es seguro usarlo desde varias goroutines a la vez.
+type Updater struct {
+	records         []Record
+	providers       map[string]provider.DNSProvider // por nombre (Name del proveedor)
+	defaultProvider string                          // proveedor de los registros sin zona asignada
+	zoneProviders   map[string]string               // zona -> proveedor
+	ipLists         []provider.IPList
+	ipSource        IPSource
+	checkConnection func(ctx context.Context) bool
+	notifiers       []Notifier
+	notifier        Notifier // notificadores combinados
+	logger          *slog.Logger
+	createMissing   bool
+	interval        time.Duration
+	shutdownGrace   time.Duration
+	shutdownNotify  bool
+	dryRun          bool  // calcular el plan sin escribir ni avisar
+	plan            *Plan // plan del ciclo en curso (solo en dry-run)
+
+	internetDown     bool
+	disconnectedAt   *time.Time
+	startupEmailSent bool
+	authAlertSent    bool                 // ya se alertó de un error de autenticación
+	disabled         map[recordKey]string // registros desactivados -> motivo
+	batchUnsupported map[string]bool      // proveedores que rechazaron un lote
+	unreachable      map[string]string    // familia -> IP no pública ya avisada
+	shutdown         context.Context      // contexto de Run; cancelado al solicitar el apagado
+}
+
+// zoneTarget es una zona de un proveedor con los registros configurados que contiene
+type zoneTarget struct {
+	provider provider.DNSProvider
+	zone     provider.Zone
+	records  []Record
+}
+
+// Run ejecuta el bucle principal hasta que se cancele ctx. Al cancelarse se interrumpe
+// la espera entre ciclos y el trabajo en curso (actualización y notificación del
+// registro actual) dispone del periodo de gracia (WithShutdownGrace) para terminar
+// antes de ser cancelado. El correo de apagado tiene su propio timeout.
+// Retorna ErrShutdownTimeout si hubo que cancelar trabajo en curso (nunca con un
+// periodo de gracia de 0, en el que se cancela sin esperar).
+func (u *Updater) Run(ctx context.Context) error {
+	u.logger.Info("Iniciando bucle principal de verificación de IP")
+	u.shutdown = ctx
+	startedAt := time.Now()
+
+	// El trabajo en curso usa su propio contexto, que sobrevive a la señal de
+	// apagado durante el periodo de gracia
+	workCtx, cancelWork := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelWork()
+
+	var forced atomic.Bool
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-workCtx.Done():
+			return
+		}
+		if u.shutdownGrace <= 0 {
+			// Sin periodo de gracia el trabajo en curso se cancela de inmediato; no es
+			// un timeout y no se debe competir con el temporizador
+			cancelWork()
+			return
+		}
+		select {
+		case <-time.After(u.shutdownGrace):
+			u.logger.Error("El trabajo en curso no terminó dentro del periodo de gracia, cancelándolo")
+			forced.Store(true)
+			cancelWork()
+		case <-workCtx.Done():
+		}
+	}()
+
+	for ctx.Err() == nil {
+		// Los errores del ciclo ya quedaron en el log; se reintenta en el siguiente
+		_ = u.runCycle(workCtx)
+		u.sleep(ctx)
+	}
+
+	cancelWork()
+	u.logger.Info("Bucle principal detenido")
+
+	if u.shutdownNotify {
+		notifyCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownNotifyTimeout)
+		defer cancel()
+		if err := u.notifier.SendShutdownNotification(notifyCtx, time.Since(startedAt)); err != nil {
+			u.logger.Error(fmt.Sprintf("Error enviando correo de apagado: %v", err))
+		} else {
+			u.logger.Info("Correo de apagado enviado")
+		}
+	}
+
+	if forced.Load() {
+		return ErrShutdownTimeout
+	}
+	return nil
+}
+
+// RunOnce ejecuta un solo ciclo (conexión, detección de IP y reconciliación) y
+// retorna sus errores combinados con errors.Join. Con ErrNoConnection o
+// ErrNoPublicIP no se consultó ningún proveedor.
+func (u *Updater) RunOnce(ctx context.Context) error {
+	u.shutdown = ctx
+	return u.runCycle(ctx)
+}
+
+// Preflight verifica credenciales y permisos de los proveedores que lo soportan
+// (Preflighter) con los registros que gestiona cada uno. Retorna los errores
+// combinados con errors.Join; un error que envuelve ErrAuth o ErrNotFound indica
+// un problema de configuración, el resto puede ser transitorio. Los permisos que
+// no se pudieron determinar (ErrUnverified) solo se registran como advertencia.
+func (u *Updater) Preflight(ctx context.Context) error {
+	recordNames := make(map[string][]string)
+	var names []string
+	for _, record := range u.records {
+		name := u.providerFor(record.Name)
+		if _, ok := recordNames[name]; !ok {
+			names = append(names, name)
+		}
+		recordNames[name] = append(recordNames[name], record.Name)
+	}
+
+	var errs []error
+	for _, name := range names {
+		preflighter, ok := u.providers[name].(provider.Preflighter)
+		if !ok {
+			continue
+		}
+		err := preflighter.Preflight(ctx, recordNames[name])
+		if provider.IsUnverified(err) && !provider.IsAuthError(err) && !provider.IsNotFound(err) {
+			u.logger.Warn(fmt.Sprintf("Verificación inicial de %s incompleta, se continúa: %v", name, err))
+			continue
+		}
+		if err != nil {
+			u.logger.Error(fmt.Sprintf("Verificación inicial de %s fallida: %v", name, err))
+			errs = append(errs, fmt.Errorf("proveedor %s: %w", name, err))
+			continue
+		}
+		u.logger.Info(fmt.Sprintf("Verificación inicial de %s correcta: credenciales válidas y permisos de edición DNS en %d registros", name, len(recordNames[name])))
+	}
+	return errors.Join(errs...)
+}
+
+// stopping indica si se solicitó el apagado; el ciclo no empieza trabajo nuevo
+func (u *Updater) stopping() bool {
+	return u.shutdown != nil && u.shutdown.Err() != nil
+}
+
+// runCycle ejecuta un ciclo de verificación: conexión, detección de IP y reconciliación.
+// Los errores ya se registran en el log; se retornan para RunOnce.
+func (u *Updater) runCycle(ctx context.Context) error {
+	u.logger.Debug("Iniciando ciclo de verificación")
+
+	// En dry-run el ciclo arma un plan en lugar de aplicar los cambios
+	u.plan = nil
+	if u.dryRun {
+		u.plan = &Plan{PublicIPs: make(map[string]string)}
+		defer u.finishPlan()
+	}
+
+	// Verificar conexión a internet (como en Python)
+	if !u.checkConnection(ctx) {
+		if !u.internetDown {
+			// Primera vez que se detecta sin conexión
+			now := time.Now()
+			u.disconnectedAt = &now
+			u.internetDown = true
+			u.logger.Error("No hay conexión a internet")
+			// No enviamos correo aquí porque no hay conexión para enviarlo
+		}
+		return ErrNoConnection
+	}
+
+	// Si llegamos aquí, hay conexión a internet
+	if u.internetDown {
+		// Se ha restaurado la conexión
+		u.logger.Info("Se ha restaurado la conexión a internet")
+
+		// Calcular tiempo sin conexión
+		if u.disconnectedAt != nil {
+			duration := time.Since(*u.disconnectedAt)
+			u.logger.Info(fmt.Sprintf("Tiempo sin conexión: %v", duration))
+
+			// Enviar correo de restauración
+			if err := u.notifier.SendConnectionRestoredNotification(ctx, duration); err != nil {
+				u.logger.Error(fmt.Sprintf("Error enviando correo de restauración: %v", err))
+			} else {
+				u.logger.Info("Correo de restauración de conexión enviado")
+			}
+		}
+
+		u.internetDown = false
+		u.disconnectedAt = nil
+	}
+
+	if u.stopping() {
+		return nil
+	}
+
+	// Obtener IPs públicas actuales por familia (A -> IPv4, AAAA -> IPv6)
+	currentIPs := u.detectPublicIPs(ctx)
+	if len(currentIPs) == 0 {
+		// Continuar en el siguiente ciclo
+		return ErrNoPublicIP
+	}
+	if u.plan != nil {
+		u.plan.PublicIPs = currentIPs
+	}
+
+	// Enviar correo de inicio solo la primera vez
+	if !u.startupEmailSent && !u.dryRun {
+		if err := u.notifier.SendStartupNotification(ctx, currentIPs[RecordTypeA], currentIPs[RecordTypeAAAA], u.recordNames()); err != nil {
+			u.logger.Error(fmt.Sprintf("Error enviando correo de inicio: %v", err))
+		} else {
+			u.logger.Info("Correo de inicio enviado: Verificador DNS corriendo")
+			u.startupEmailSent = true
+		}
+	}
+
+	u.logger.Debug(fmt.Sprintf("Verificando %d registros DNS", len(u.records)))
+
+	if u.stopping() {
+		return nil
+	}
+
+	// Reconciliar los registros de todas las zonas y proveedores
+	err := u.reconcile(ctx, currentIPs)
+
+	// Mantener la IP pública en las listas de IPs configuradas
+	if len(u.ipLists) > 0 && !u.stopping() {
+		if u.dryRun {
+			err = errors.Join(err, u.planIPLists(ctx, currentIPs))
+		} else {
+			err = errors.Join(err, u.syncIPLists(ctx, currentIPs))
+		}
+	}
+
+	u.logger.Debug("Ciclo completado")
+	return err
+}
+
+// syncIPLists deja la IP pública de cada familia detectada en cada lista de IPs.
+// Como en los registros DNS, un error de autenticación, rate limit o indisponibilidad
+// detiene la lista en este ciclo y se reintenta en el siguiente.
+func (u *Updater) syncIPLists(ctx context.Context, currentIPs map[string]string) error {
+	var errs []error
+	for _, list := range u.ipLists {
+		name := list.Name()
+
+		if cycleAware, ok := list.(provider.CycleAware); ok {
+			if err := cycleAware.BeginCycle(); err != nil {
+				u.logger.Error(fmt.Sprintf("Lista de IPs %s no disponible, se omite en este ciclo: %v", name, err))
+				errs = append(errs, fmt.Errorf("lista de IPs %s: %w", name, err))
+				continue
+			}
+		}
+
+		for _, recordType := range []string{RecordTypeA, RecordTypeAAAA} {
+			currentIP, ok := currentIPs[recordType]
+			if !ok {
+				continue
+			}
+			if u.stopping() {
+				return errors.Join(errs...)
+			}
+
+			change, err := list.SyncIP(ctx, recordType, currentIP)
+			if err != nil {
+				u.logger.Error(fmt.Sprintf("Error sincronizando lista de IPs %s (%s): %v", name, recordType, err))
+				errs = append(errs, fmt.Errorf("lista de IPs %s (%s): %w", name, recordType, err))
+				if u.handleAPIError(ctx, "lista de IPs "+name, err) {
+					break
+				}
+				continue
+			}
+			if change == nil {
+				u.logger.Debug(fmt.Sprintf("La lista de IPs %s ya contiene la IP actual %s", name, currentIP))
+				continue
+			}
+
+			u.logger.Info(fmt.Sprintf("Lista de IPs %s actualizada (%s): agregada %q, eliminadas [%s]", name, recordType, change.Added, strings.Join(change.Removed, ", ")))
+			if notifier, ok := u.notifier.(IPListNotifier); ok {
+				if err := notifier.SendIPListUpdateNotification(ctx, change.List, recordType, change.Added, change.Removed); err != nil {
+					u.logger.Error(fmt.Sprintf("Error enviando correo de lista de IPs: %v", err))
+				} else {
+					u.logger.Debug(fmt.Sprintf("Correo de lista de IPs enviado para %s (%s)", change.List, recordType))
+				}
+			}
+		}
+
+		if cycleAware, ok := list.(provider.CycleAware); ok {
+			if err := cycleAware.EndCycle(); err != nil {
+				u.logger.Error(fmt.Sprintf("Lista de IPs %s: %v", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// reconcile agrupa los registros configurados por proveedor y zona, obtiene el estado
+// de cada zona una sola vez y reconcilia cada registro y familia contra ese snapshot.
+// Los errores de autenticación, rate limit o indisponibilidad detienen las operaciones
+// del proveedor afectado en este ciclo (se reintenta en el siguiente).
+// Retorna los errores del ciclo combinados con errors.Join.
+func (u *Updater) reconcile(ctx context.Context, currentIPs map[string]string) error {
+	halted := make(map[string]bool) // proveedores detenidos en este ciclo
+	var errs []error
+	var started []provider.DNSProvider
+	defer func() {
+		for _, p := range started {
+			if err := p.(provider.CycleAware).EndCycle(); err != nil {
+				u.logger.Error(fmt.Sprintf("Proveedor %s: %v", p.Name(), err))
+			}
+		}
+	}()
+
+	// Resolver la zona de cada registro en su proveedor (con caché en el proveedor)
+	var targets []*zoneTarget
+	targetsByZone := make(map[string]*zoneTarget)
+	begun := make(map[string]bool)
+	for _, record := range u.records {
+		name := u.providerFor(record.Name)
+		p := u.providers[name]
+		if halted[name] {
+			continue
+		}
+
+		// No insistir contra un proveedor no disponible (p. ej. circuit breaker abierto)
+		if !begun[name] {
+			begun[name] = true
+			if cycleAware, ok := p.(provider.CycleAware); ok {
+				if err := cycleAware.BeginCycle(); err != nil {
+					u.logger.Error(fmt.Sprintf("Proveedor %s no disponible, se omiten sus registros en este ciclo: %v", name, err))
+					errs = append(errs, fmt.Errorf("proveedor %s: %w", name, err))
+					halted[name] = true
+					continue
+				}
+				started = append(started, p)
+			}
+		}
+
+		zone, err := p.ResolveZone(ctx, record.Name)
+		if err != nil {
+			u.logger.Error(fmt.Sprintf("Error resolviendo zona de %s en %s: %v", record.Name, name, err))
+			errs = append(errs, fmt.Errorf("zona de %s en %s: %w", record.Name, name, err))
+			if u.handleAPIError(ctx, name, err) {
+				halted[name] = true
+			}
+			continue
+		}
+
+		zoneKey := name + "/" + zone.ID
+		target, ok := targetsByZone[zoneKey]
+		if !ok {
+			target = &zoneTarget{provider: p, zone: *zone}
+			targetsByZone[zoneKey] = target
+			targets = append(targets, target)
+		}
+		target.records = append(target.records, record)
+	}
+
+	for _, target := range targets {
+		name := target.provider.Name()
+		if halted[name] {
+			continue
+		}
+		u.logger.Debug(fmt.Sprintf("Reconciliando zona %s (%s) en %s: %d registros", target.zone.Name, target.zone.ID, name, len(target.records)))
+
+		ok, err := u.reconcileZone(ctx, target, currentIPs)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if !ok {
+			halted[name] = true
+		}
+		if u.stopping() {
+			return errors.Join(errs...)
+		}
+	}
+
+	// Ciclo completo sin proveedores detenidos: se puede volver a alertar
+	if len(halted) == 0 {
+		u.authAlertSent = false
+	}
+	return errors.Join(errs...)
+}
+
+// reconcileZone reconcilia los registros de una zona contra su snapshot.
+// Retorna false si se deben detener las operaciones del proveedor en este ciclo,
+// junto con los errores de la zona combinados con errors.Join.
+func (u *Updater) reconcileZone(ctx context.Context, target *zoneTarget, currentIPs map[string]string) (bool, error) {
+	name := target.provider.Name()
+	var errs []error
+
+	// Obtener el estado de la zona una sola vez por ciclo
+	snapshot, err := u.loadSnapshot(ctx, target, target.records)
+	if err != nil {
+		u.logger.Error(fmt.Sprintf("Error obteniendo estado de la zona %s en %s: %v", target.zone.Name, name, err))
+		err = fmt.Errorf("zona %s en %s: %w", target.zone.Name, name, err)
+		return !u.handleAPIError(ctx, name, err), err
+	}
+
+	// Planificar los cambios de cada registro y cada familia gestionada contra el snapshot
+	var ops []*recordOp
+	for _, record := range target.records {
+		for _, recordType := range record.Types {
+			currentIP, ok := currentIPs[recordType]
+			if !ok {
+				u.logger.Debug(fmt.Sprintf("Sin IP pública para %s, se omite %s", recordType, record.Name))
+				u.planSkip(target, snapshot, record.Name, recordType, "sin IP pública "+familyName(recordType))
+				continue
+			}
+			key := newRecordKey(record.Name, recordType)
+			if reason, disabled := u.disabled[key]; disabled {
+				u.logger.Debug(fmt.Sprintf("Registro %s (%s) desactivado: %s", record.Name, recordType, reason))
+				u.planSkip(target, snapshot, record.Name, recordType, "desactivado: "+reason)
+				continue
+			}
+			op, err := u.planRecord(target, snapshot, record, recordType, currentIP)
+			if err != nil {
+				u.logger.Error(fmt.Sprintf("Error procesando registro %s (%s): %v", record.Name, recordType, err))
+				errs = append(errs, fmt.Errorf("registro %s (%s): %w", record.Name, recordType, err))
+				u.handleRecordError(ctx, name, key, err)
+				u.planSkip(target, snapshot, record.Name, recordType, err.Error())
+				continue
+			}
+			if op == nil {
+				u.planSkip(target, snapshot, record.Name, recordType, "al día")
+				continue
+			}
+			u.planRecordOp(target, op)
+			ops = append(ops, op)
+		}
+	}
+
+	// En dry-run los cambios quedan en el plan
+	if u.dryRun {
+		return true, errors.Join(errs...)
+	}
+
+	// Con varios cambios, aplicarlos juntos en un lote atómico si el proveedor lo admite
+	if len(ops) > 1 {
+		applied, cont, err := u.applyBatch(ctx, target, snapshot, ops)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if applied || err != nil {
+			return cont, errors.Join(errs...)
+		}
+	}
+
+	// Aplicar cada cambio por separado
+	for _, op := range ops {
+		if u.stopping() {
+			u.logger.Info("Apagado solicitado: no se procesan más registros en este ciclo")
+			return true, errors.Join(errs...)
+		}
+		if err := u.applyRecord(ctx, target, snapshot, op); err != nil {
+			u.logger.Error(fmt.Sprintf("Error procesando registro %s (%s): %v", op.desired.Name, op.recordType, err))
+			errs = append(errs, fmt.Errorf("registro %s (%s): %w", op.desired.Name, op.recordType, err))
+			if u.handleRecordError(ctx, name, op.key, err) {
+				return false, errors.Join(errs...)
+			}
+			// Continuar con el siguiente registro
+			continue
+		}
+	}
+	return true, errors.Join(errs...)
+}
+
+// detectPublicIPs obtiene la IP pública de cada familia gestionada.
+// Un fallo en una familia no impide procesar la otra. Una IP que no es pública
+// (privada, bogon o CGNAT) se descarta y se avisa con reportUnreachable.
+func (u *Updater) detectPublicIPs(ctx context.Context) map[string]string {
+	currentIPs := make(map[string]string)
+
+	for _, recordType := range []string{RecordTypeA, RecordTypeAAAA} {
+		if !u.managesType(recordType) {
+			continue
+		}
+		family := familyName(recordType)
+		currentIP, err := u.ipSource.PublicIP(ctx, recordType)
+		if err == nil {
+			// Las fuentes propias (WithIPSource) no pasan por la validación de internal/ip
+			err = ip.CheckPublic(currentIP)
+		}
+		if err != nil {
+			u.logger.Error(fmt.Sprintf("Error obteniendo IP pública %s: %v", family, err))
+			var addrErr *AddressError
+			if errors.As(err, &addrErr) {
+				u.reportUnreachable(ctx, recordType, addrErr)
+			}
+			continue
+		}
+		if address, ok := u.unreachable[recordType]; ok {
+			u.logger.Info(fmt.Sprintf("Se detectó de nuevo una IP pública %s (la anterior, %s, no era pública)", family, address))
+			delete(u.unreachable, recordType)
+		}
+		u.logger.Info(fmt.Sprintf("IP pública %s detectada: %s", family, currentIP))
+		currentIPs[recordType] = currentIP
+	}
+
+	return currentIPs
+}
+
+// recordOp es un cambio planificado de un registro: una creación o una
+// actualización de los campos que difieren
+type recordOp struct {
+	desired    Record
+	recordType string
+	key        recordKey
+
+	create    bool
+	newRecord provider.Record // registro a crear (create)
+
+	current   provider.Record // registro actual (actualización)
+	update    provider.RecordUpdate
+	oldIP     string
+	newIP     string
+	ipChanged bool
+	changes   []string // ajustes que difieren
+}
+
+// planRecord compara el registro deseado con el snapshot de la zona y retorna el
+// cambio necesario, o nil si el registro está al día. No hace peticiones.
+func (u *Updater) planRecord(target *zoneTarget, snapshot zoneSnapshot, desired Record, recordType, currentIP string) (*recordOp, error) {
+	recordName := desired.Name
+	u.logger.Debug(fmt.Sprintf("Procesando registro: %s (%s)", recordName, recordType))
+
+	op := &recordOp{desired: desired, recordType: recordType, key: newRecordKey(recordName, recordType), newIP: currentIP}
+
+	// Buscar registro actual en el snapshot de la zona
+	record, ok := snapshot.get(recordName, recordType)
+	if !ok {
+		if !u.createMissing {
+			return nil, fmt.Errorf("no se encontró registro DNS %s con nombre %s (active CREATE_MISSING o WithCreateMissing para crearlo)", recordType, recordName)
+		}
+		if !u.dryRun {
+			u.logger.Info(fmt.Sprintf("Registro %s (%s) no existe. Creándolo con IP %s...", recordName, recordType, currentIP))
+		}
+		op.create = true
+		op.newRecord = newRecord(desired, target.provider.Capabilities(), recordType, currentIP)
+		return op, nil
+	}
+
+	u.logger.Debug(fmt.Sprintf("Registro DNS encontrado: %s %s -> %s (ID: %s)", record.Name, record.Type, record.Content, record.ID))
+
+	// Comparar ajustes (TTL, proxied, comentario, etiquetas) y luego la IP
+	update, changes := diffSettings(desired, record, target.provider.Capabilities())
+	for _, change := range changes {
+		u.logger.Info(fmt.Sprintf("Ajuste diferente detectado para %s (%s): %s", recordName, recordType, change))
+	}
+
+	oldIP := record.Content
+	ipChanged := oldIP != currentIP
+	if ipChanged {
+		update.Content = currentIP
+		if !u.dryRun {
+			u.logger.Info(fmt.Sprintf("IP diferente detectada para %s (%s): DNS=%s, Actual=%s. Actualizando...", recordName, recordType, oldIP, currentIP))
+		}
+	}
+
+	if !ipChanged && len(changes) == 0 {
+		u.logger.Debug(fmt.Sprintf("IP del registro %s (%s) coincide con IP actual (%s), no se requiere actualización", recordName, recordType, currentIP))
+		return nil, nil
+	}
+
+	op.current = record
+	op.update = update
+	op.oldIP = oldIP
+	op.ipChanged = ipChanged
+	op.changes = changes
+	return op, nil
+}
+
+// applyRecord aplica un cambio planificado con una petición propia
+func (u *Updater) applyRecord(ctx context.Context, target *zoneTarget, snapshot zoneSnapshot, op *recordOp) error {
+	if op.create {
+		created, err := target.provider.CreateRecord(ctx, target.zone, op.newRecord)
+		if err != nil {
+			return fmt.Errorf("error creando registro DNS: %w", err)
+		}
+		u.finishRecord(ctx, snapshot, op, *created)
+		return nil
+	}
+
+	// Actualizar registro en el proveedor (solo los campos que difieren)
+	updated, err := target.provider.UpdateRecord(ctx, target.zone, op.current, op.update)
+	if err != nil {
+		return fmt.Errorf("error actualizando registro DNS: %w", err)
+	}
+	u.finishRecord(ctx, snapshot, op, *updated)
+	return nil
+}
+
+// finishRecord registra en el snapshot el registro resultante de un cambio
+// aplicado (para no repetirlo en este ciclo) y envía su notificación
+func (u *Updater) finishRecord(ctx context.Context, snapshot zoneSnapshot, op *recordOp, result provider.Record) {
+	snapshot[newRecordKey(result.Name, result.Type)] = result
+	u.reportRecord(ctx, op, result.ID)
+}
+
+// reportRecord registra en el log y notifica un cambio ya aplicado. id es el del
+// registro creado, vacío si el proveedor no lo informó.
+func (u *Updater) reportRecord(ctx context.Context, op *recordOp, id string) {
+	recordName, recordType := op.desired.Name, op.recordType
+
+	if op.create {
+		u.logger.Info(fmt.Sprintf("Registro %s (%s) creado exitosamente: %s (ID: %s)", recordName, recordType, op.newIP, orDash(id)))
+
+		// Enviar notificación por correo
+		if err := u.notifier.SendDNSCreateNotification(ctx, recordName, recordType, op.newIP); err != nil {
+			u.logger.Error(fmt.Sprintf("Error enviando correo de creación: %v", err))
+			// No retornamos error aquí, el registro ya se creó
+		} else {
+			u.logger.Debug(fmt.Sprintf("Correo de creación enviado para %s (%s)", recordName, recordType))
+		}
+		return
+	}
+
+	if op.ipChanged {
+		u.logger.Info(fmt.Sprintf("Registro %s (%s) actualizado exitosamente: %s -> %s", recordName, recordType, op.oldIP, op.newIP))
+	}
+	if len(op.changes) > 0 {
+		u.logger.Info(fmt.Sprintf("Ajustes del registro %s (%s) corregidos: %s", recordName, recordType, strings.Join(op.changes, "; ")))
+	}
+
+	// Enviar notificación por correo
+	var err error
+	if op.ipChanged {
+		err = u.notifier.SendDNSUpdateNotification(ctx, recordName, recordType, op.oldIP, op.newIP, op.changes)
+	} else {
+		err = u.notifier.SendDNSSettingsNotification(ctx, recordName, recordType, op.changes)
+	}
+	if err != nil {
+		u.logger.Error(fmt.Sprintf("Error enviando correo de notificación: %v", err))
+		// No retornamos error aquí, el cambio de DNS ya se hizo
+	} else {
+		u.logger.Debug(fmt.Sprintf("Correo de notificación enviado para %s (%s)", recordName, recordType))
+	}
+}
+
+// sleep espera hasta el siguiente ciclo o hasta que se cancele ctx
+func (u *Updater) sleep(ctx context.Context) {
+	duration := u.interval
+	u.logger.Debug(fmt.Sprintf("Durmiendo por %v", duration))
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
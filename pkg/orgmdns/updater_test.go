@@ -0,0 +1,340 @@
+package orgmdns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/osmargm1202/orgmdns/internal/provider"
+)
+
+// IPs públicas detectadas en las pruebas
+const (
+	testIPv4 = "8.8.8.8"
+	testIPv6 = "2606:4700:4700::1111"
+)
+
+// fakeProvider es un proveedor en memoria con una sola zona que registra las escrituras
+type fakeProvider struct {
+	caps    provider.Capabilities
+	zone    provider.Zone
+	records []provider.Record
+
+	listErr   error
+	updateErr error
+
+	creates []provider.Record
+	updates []provider.RecordChange
+}
+
+func newFakeProvider(records ...provider.Record) *fakeProvider {
+	return &fakeProvider{
+		caps:    provider.Capabilities{TTL: true, ListZone: true},
+		zone:    provider.Zone{ID: "zone-1", Name: "example.com"},
+		records: records,
+	}
+}
+
+func (p *fakeProvider) Name() string                        { return "fake" }
+func (p *fakeProvider) Capabilities() provider.Capabilities { return p.caps }
+func (p *fakeProvider) DeleteRecord(context.Context, provider.Zone, provider.Record) error {
+	return nil
+}
+
+func (p *fakeProvider) ResolveZone(ctx context.Context, recordName string) (*provider.Zone, error) {
+	zone := p.zone
+	return &zone, nil
+}
+
+func (p *fakeProvider) ListRecords(ctx context.Context, zone provider.Zone, filter provider.ListFilter) ([]provider.Record, error) {
+	if p.listErr != nil {
+		return nil, p.listErr
+	}
+	var result []provider.Record
+	for _, record := range p.records {
+		if filter.Type == "" || record.Type == filter.Type {
+			result = append(result, record)
+		}
+	}
+	return result, nil
+}
+
+func (p *fakeProvider) GetRecord(ctx context.Context, zone provider.Zone, name, recordType string) (*provider.Record, error) {
+	for _, record := range p.records {
+		if record.Name == name && record.Type == recordType {
+			return &record, nil
+		}
+	}
+	return nil, provider.ErrNotFound
+}
+
+func (p *fakeProvider) CreateRecord(ctx context.Context, zone provider.Zone, record provider.Record) (*provider.Record, error) {
+	p.creates = append(p.creates, record)
+	record.ID = fmt.Sprintf("id-%d", len(p.records)+1)
+	p.records = append(p.records, record)
+	return &record, nil
+}
+
+func (p *fakeProvider) UpdateRecord(ctx context.Context, zone provider.Zone, current provider.Record, update provider.RecordUpdate) (*provider.Record, error) {
+	p.updates = append(p.updates, provider.RecordChange{Current: current, Update: update})
+	if p.updateErr != nil {
+		return nil, p.updateErr
+	}
+	updated := provider.ApplyUpdate(current, update)
+	for i := range p.records {
+		if p.records[i].ID == current.ID {
+			p.records[i] = updated
+		}
+	}
+	return &updated, nil
+}
+
+// writes retorna la cantidad de escrituras individuales recibidas
+func (p *fakeProvider) writes() int {
+	return len(p.creates) + len(p.updates)
+}
+
+// fakeBatcher agrega ApplyBatch al proveedor en memoria
+type fakeBatcher struct {
+	*fakeProvider
+	batchErr error
+	batches  int
+}
+
+func (p *fakeBatcher) ApplyBatch(ctx context.Context, zone provider.Zone, creates []provider.Record, updates []provider.RecordChange) ([]provider.Record, []provider.Record, error) {
+	p.batches++
+	if p.batchErr != nil {
+		return nil, nil, p.batchErr
+	}
+	var created, updated []provider.Record
+	for _, record := range creates {
+		created = append(created, record)
+	}
+	for _, change := range updates {
+		updated = append(updated, provider.ApplyUpdate(change.Current, change.Update))
+	}
+	return created, updated, nil
+}
+
+// fakeNotifier registra los avisos enviados
+type fakeNotifier struct {
+	sent []string
+}
+
+func (n *fakeNotifier) SendDNSUpdateNotification(ctx context.Context, recordName, recordType, oldIP, newIP string, changes []string) error {
+	n.sent = append(n.sent, "update "+recordName)
+	return nil
+}
+
+func (n *fakeNotifier) SendDNSSettingsNotification(ctx context.Context, recordName, recordType string, changes []string) error {
+	n.sent = append(n.sent, "settings "+recordName)
+	return nil
+}
+
+func (n *fakeNotifier) SendDNSCreateNotification(ctx context.Context, recordName, recordType, ip string) error {
+	n.sent = append(n.sent, "create "+recordName)
+	return nil
+}
+
+func (n *fakeNotifier) SendStartupNotification(ctx context.Context, currentIPv4, currentIPv6 string, recordNames []string) error {
+	n.sent = append(n.sent, "startup")
+	return nil
+}
+
+func (n *fakeNotifier) SendConnectionRestoredNotification(ctx context.Context, duration time.Duration) error {
+	n.sent = append(n.sent, "restored")
+	return nil
+}
+
+func (n *fakeNotifier) SendShutdownNotification(ctx context.Context, uptime time.Duration) error {
+	n.sent = append(n.sent, "shutdown")
+	return nil
+}
+
+func (n *fakeNotifier) SendErrorNotification(ctx context.Context, errorMsg string) error {
+	n.sent = append(n.sent, "error")
+	return nil
+}
+
+// newTestUpdater crea un Updater con IP fija, conexión disponible y sin log
+func newTestUpdater(t *testing.T, p provider.DNSProvider, notifier Notifier, opts ...Option) *Updater {
+	t.Helper()
+	opts = append([]Option{
+		WithProvider(p),
+		WithIPSource(IPSourceFunc(func(ctx context.Context, recordType string) (string, error) {
+			if recordType == RecordTypeAAAA {
+				return testIPv6, nil
+			}
+			return testIPv4, nil
+		})),
+		WithConnectivityCheck(func(ctx context.Context) bool { return true }),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithNotifier(notifier),
+	}, opts...)
+
+	u, err := New(opts...)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	return u
+}
+
+func TestRunOnceReconcile(t *testing.T) {
+	p := newFakeProvider(
+		provider.Record{ID: "r1", Type: "A", Name: "home.example.com", Content: "198.51.100.1", TTL: 300},
+		provider.Record{ID: "r2", Type: "A", Name: "nas.example.com", Content: testIPv4, TTL: 300},
+		provider.Record{ID: "r3", Type: "AAAA", Name: "nas.example.com", Content: testIPv6, TTL: 600},
+	)
+	notifier := &fakeNotifier{}
+	u := newTestUpdater(t, p, notifier,
+		WithCreateMissing(true),
+		WithRecords(
+			Record{Name: "home.example.com", Types: []string{RecordTypeA}},
+			Record{Name: "nas.example.com", Types: []string{RecordTypeA, RecordTypeAAAA}, TTL: 300},
+			Record{Name: "new.example.com", Types: []string{RecordTypeA}},
+		),
+	)
+
+	if err := u.RunOnce(context.Background()); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	// home: IP distinta; nas AAAA: TTL distinto; nas A: al día; new: no existe
+	if len(p.updates) != 2 || len(p.creates) != 1 {
+		t.Fatalf("actualizaciones = %+v, creaciones = %+v", p.updates, p.creates)
+	}
+	if got := p.updates[0]; got.Current.ID != "r1" || got.Update.Content != testIPv4 || got.Update.TTL != 0 {
+		t.Errorf("actualización de home = %+v", got)
+	}
+	if got := p.updates[1]; got.Current.ID != "r3" || got.Update.Content != "" || got.Update.TTL != 300 {
+		t.Errorf("actualización de nas = %+v", got)
+	}
+	if got := p.creates[0]; got.Name != "new.example.com" || got.Content != testIPv4 || got.TTL != 1 {
+		t.Errorf("creación = %+v", got)
+	}
+	want := "[startup update home.example.com settings nas.example.com create new.example.com]"
+	if fmt.Sprint(notifier.sent) != want {
+		t.Errorf("avisos = %v, want %s", notifier.sent, want)
+	}
+
+	// El segundo ciclo encuentra todo al día
+	if err := u.RunOnce(context.Background()); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if p.writes() != 3 {
+		t.Errorf("escrituras tras el segundo ciclo = %d, want 3", p.writes())
+	}
+}
+
+func TestRunOnceMissingWithoutCreate(t *testing.T) {
+	p := newFakeProvider()
+	u := newTestUpdater(t, p, &fakeNotifier{}, WithRecords(Record{Name: "home.example.com"}))
+
+	err := u.RunOnce(context.Background())
+	if err == nil || p.writes() != 0 {
+		t.Errorf("error = %v, escrituras = %d, want error y ninguna escritura", err, p.writes())
+	}
+}
+
+func TestRunOnceValidationDisablesRecord(t *testing.T) {
+	p := newFakeProvider(provider.Record{ID: "r1", Type: "A", Name: "home.example.com", Content: "198.51.100.1"})
+	p.updateErr = fmt.Errorf("contenido inválido: %w", provider.ErrValidation)
+	notifier := &fakeNotifier{}
+	u := newTestUpdater(t, p, notifier, WithRecords(Record{Name: "home.example.com"}))
+
+	if err := u.RunOnce(context.Background()); !errors.Is(err, provider.ErrValidation) {
+		t.Fatalf("error = %v, want ErrValidation", err)
+	}
+	// El registro queda desactivado: el siguiente ciclo no lo reintenta
+	if err := u.RunOnce(context.Background()); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if len(p.updates) != 1 {
+		t.Errorf("actualizaciones = %d, want 1", len(p.updates))
+	}
+	if fmt.Sprint(notifier.sent) != "[startup error]" {
+		t.Errorf("avisos = %v", notifier.sent)
+	}
+}
+
+func TestRunOnceAuthErrorAlertsOnce(t *testing.T) {
+	p := newFakeProvider()
+	p.listErr = fmt.Errorf("token inválido: %w", provider.ErrAuth)
+	notifier := &fakeNotifier{}
+	u := newTestUpdater(t, p, notifier, WithRecords(Record{Name: "home.example.com"}))
+
+	for i := 0; i < 2; i++ {
+		if err := u.RunOnce(context.Background()); !errors.Is(err, provider.ErrAuth) {
+			t.Fatalf("ciclo %d: error = %v, want ErrAuth", i, err)
+		}
+	}
+	if fmt.Sprint(notifier.sent) != "[startup error]" {
+		t.Errorf("avisos = %v, want un solo aviso de error", notifier.sent)
+	}
+
+	// Tras un ciclo sin errores se puede volver a alertar
+	p.listErr = nil
+	p.records = []provider.Record{{ID: "r1", Type: "A", Name: "home.example.com", Content: testIPv4}}
+	if err := u.RunOnce(context.Background()); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	p.listErr = provider.ErrAuth
+	_ = u.RunOnce(context.Background())
+	if fmt.Sprint(notifier.sent) != "[startup error error]" {
+		t.Errorf("avisos = %v", notifier.sent)
+	}
+}
+
+func TestRunOnceBatch(t *testing.T) {
+	tests := []struct {
+		name            string
+		batchErr        error
+		wantErr         error // nil = sin error
+		wantWrites      int   // escrituras individuales en el primer ciclo
+		wantSecondBatch bool  // el segundo ciclo vuelve a intentar el lote
+	}{
+		{name: "lote aplicado", wantSecondBatch: true},
+		{name: "lote no admitido", batchErr: fmt.Errorf("endpoint no disponible: %w", provider.ErrBatchUnsupported), wantWrites: 2},
+		{name: "lote rechazado por validación", batchErr: fmt.Errorf("registro inválido: %w", provider.ErrValidation), wantWrites: 2, wantSecondBatch: true},
+		{name: "proveedor no disponible", batchErr: fmt.Errorf("timeout: %w", provider.ErrUnavailable), wantErr: provider.ErrUnavailable, wantSecondBatch: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// records retorna la zona con las IPs anteriores
+			records := func() []provider.Record {
+				return []provider.Record{
+					{ID: "r1", Type: "A", Name: "a.example.com", Content: "198.51.100.1"},
+					{ID: "r2", Type: "A", Name: "b.example.com", Content: "198.51.100.1"},
+				}
+			}
+			p := &fakeBatcher{fakeProvider: newFakeProvider(records()...), batchErr: tt.batchErr}
+			u := newTestUpdater(t, p, &fakeNotifier{}, WithRecords(
+				Record{Name: "a.example.com"},
+				Record{Name: "b.example.com"},
+			))
+
+			err := u.RunOnce(context.Background())
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if p.batches != 1 || p.writes() != tt.wantWrites {
+				t.Errorf("lotes = %d, escrituras = %d, want 1 y %d", p.batches, p.writes(), tt.wantWrites)
+			}
+
+			// Segundo ciclo con la zona otra vez desactualizada
+			p.records = records()
+			_ = u.RunOnce(context.Background())
+			if got := p.batches == 2; got != tt.wantSecondBatch {
+				t.Errorf("lotes tras el segundo ciclo = %d", p.batches)
+			}
+		})
+	}
+}